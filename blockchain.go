@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"math/big"
 
 	"github.com/umbracle/ethgo"
@@ -56,24 +57,18 @@ type blockchainInterface interface {
 	// GetReceiptsByHash returns the receipts for a block hash
 	GetReceiptsByHash(hash ethgo.Hash) ([]*ethgo.Receipt, error)
 
-	// EstimateGas estimates the gas to run the transactio
-	EstimateGas(tx *ethgo.Transaction, header *ethgo.Block) (uint64, error)
-
-	// Calls calls the transaction
+	// Calls calls the transaction. It returns a RevertError if the execution reverts
 	Call(tx *ethgo.Transaction, header *ethgo.Block) ([]byte, error)
 
 	// AddTx adds a new transaction to the tx pool
 	AddTx(tx []byte) (ethgo.Hash, error)
 
-	// GetTransactionByHash returns a transaction by its hash
+	// GetTransactionByHash returns a transaction by its hash, either mined or pending
 	GetTransactionByHash(hash ethgo.Hash) (*TransactionResult, error)
 
 	// SubscribeEvents subscribes for chain head events
 	SubscribeEvents() Subscription
 
-	// GetAvgGasPrice returns the average gas price
-	GetAvgGasPrice() *big.Int
-
 	// GetBlockByHash gets a block using the provided hash
 	GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, bool)
 
@@ -91,11 +86,265 @@ type blockchainInterface interface {
 
 	// GetCode returns a code by its hash
 	GetCode(hash ethgo.Hash) ([]byte, error)
+}
+
+// FinalityBackend is an optional interface for backends that track the
+// safe and finalized blocks of the chain
+type FinalityBackend interface {
+	// SafeHeader returns the latest safe block
+	SafeHeader() (*ethgo.Block, bool)
+
+	// FinalizedHeader returns the latest finalized block
+	FinalizedHeader() (*ethgo.Block, bool)
+}
+
+// PendingBackend is an optional interface for backends that build a pending block
+type PendingBackend interface {
+	// PendingBlock returns the pending block with its transactions
+	PendingBlock() (*ethgo.Block, bool)
+}
+
+// FeeMarketBackend is an optional interface for backends that support EIP-1559
+type FeeMarketBackend interface {
+	// BaseFee returns the base fee of the block or nil if the
+	// block is before the london fork
+	BaseFee(block *ethgo.Block) *big.Int
+}
+
+// HeaderFieldsBackend is an optional interface for backends that store the
+// header fields of the blocks that are not part of ethgo.Block. Otherwise,
+// the fields are zero in the jsonrpc blocks.
+type HeaderFieldsBackend interface {
+	// HeaderFields returns the extra header fields of the block
+	HeaderFields(block *ethgo.Block) *HeaderFields
+}
+
+// HeaderFields are the header fields of a block that are not part of ethgo.Block
+type HeaderFields struct {
+	LogsBloom       []byte
+	MixHash         ethgo.Hash
+	Nonce           [8]byte
+	Size            uint64
+	TotalDifficulty *big.Int
+}
+
+// SyncBackend is an optional interface for backends that report their sync status
+type SyncBackend interface {
+	// SyncStatus returns the sync progress of the node and false if the node is synced
+	SyncStatus() (*SyncProgress, bool)
+}
+
+// SyncProgress is the sync progress of the node
+type SyncProgress struct {
+	StartingBlock uint64
+	CurrentBlock  uint64
+	HighestBlock  uint64
+}
 
+// LogsBackend is an optional interface for backends that search logs. Otherwise,
+// the logs are searched by scanning the receipts of the blocks in the range.
+type LogsBackend interface {
 	// GetLogs returns an array of logs given some filter input
 	GetLogs(input *GetLogsInput) ([]*ethgo.Log, error)
 }
 
+// BloomBackend is an optional interface for backends that store the logs bloom of
+// the blocks. It is used to skip the receipts of the blocks without matching logs.
+type BloomBackend interface {
+	// LogsBloom returns the logs bloom of the block
+	LogsBloom(block *ethgo.Block) []byte
+}
+
+// SubmitBackend is an optional interface for backends that add
+// transactions to the pool with submission options
+type SubmitBackend interface {
+	// AddTxWithOptions adds a new transaction to the tx pool with the submission options
+	AddTxWithOptions(tx []byte, opts *SubmitOptions) (ethgo.Hash, error)
+}
+
+// SubmitOptions are the options of the submission of a transaction
+type SubmitOptions struct {
+	// Private is whether the transaction is not gossiped to the peers
+	Private bool
+
+	// Conditions are the conditions for the inclusion of the transaction if any.
+	// They are validated at the current header before the submission but the
+	// backend has to check them again when the transaction is included.
+	Conditions *TxConditions
+}
+
+// TxConditions are the conditions for the inclusion of a transaction. Only the non nil fields are checked.
+type TxConditions struct {
+	// KnownAccounts are the expected storage of the accounts
+	KnownAccounts map[ethgo.Address]*KnownAccount
+
+	BlockNumberMin *uint64
+	BlockNumberMax *uint64
+	TimestampMin   *uint64
+	TimestampMax   *uint64
+}
+
+// KnownAccount is the expected storage of an account, either
+// the storage root or the value of some storage slots
+type KnownAccount struct {
+	StorageRoot *ethgo.Hash
+	Slots       map[ethgo.Hash]ethgo.Hash
+}
+
+// GasPriceBackend is an optional interface for backends that override
+// the gas price suggested by the built-in gas price oracle
+type GasPriceBackend interface {
+	// GetAvgGasPrice returns the average gas price
+	GetAvgGasPrice() *big.Int
+}
+
+// GasEstimatorBackend is an optional interface for backends that estimate the gas
+// of a transaction. Otherwise, the gas is estimated with a binary search over Call
+type GasEstimatorBackend interface {
+	// EstimateGas estimates the gas to run the transaction
+	EstimateGas(tx *ethgo.Transaction, header *ethgo.Block) (uint64, error)
+}
+
+// OverrideBackend is an optional interface for backends that execute
+// calls with state and block overrides
+type OverrideBackend interface {
+	// CallWithOverrides calls the transaction on top of the overridden state and block
+	CallWithOverrides(tx *ethgo.Transaction, header *ethgo.Block, overrides *CallOverrides) ([]byte, error)
+}
+
+// CallOverrides are the overrides applied to the state and the block of a call
+type CallOverrides struct {
+	// State are the overrides of the accounts
+	State map[ethgo.Address]*AccountOverride
+
+	// Block is the override of the block context
+	Block *BlockOverride
+}
+
+// account returns the override of the account if any
+func (c *CallOverrides) account(addr ethgo.Address) (*AccountOverride, bool) {
+	if c == nil {
+		return nil, false
+	}
+	account, ok := c.State[addr]
+	return account, ok
+}
+
+// AccountOverride is the override of an account. Only the non nil fields
+// are overridden. State replaces the whole storage of the account
+// while StateDiff only replaces the given slots.
+type AccountOverride struct {
+	Nonce     *uint64
+	Code      []byte
+	Balance   *big.Int
+	State     map[ethgo.Hash]ethgo.Hash
+	StateDiff map[ethgo.Hash]ethgo.Hash
+}
+
+// BlockOverride is the override of the block context. Only the non nil fields are overridden.
+type BlockOverride struct {
+	Number   *uint64
+	Time     *uint64
+	GasLimit *uint64
+	Coinbase *ethgo.Address
+	BaseFee  *big.Int
+}
+
+// ProofBackend is an optional interface for backends that generate the
+// merkle proofs of the accounts and their storage (EIP-1186)
+type ProofBackend interface {
+	// GetProof returns the proof of the account and of the storage slots in the
+	// state root. The storage proofs are returned in the same order as the slots.
+	GetProof(root ethgo.Hash, addr ethgo.Address, slots []ethgo.Hash) (*AccountProof, error)
+}
+
+// AccountProof is the merkle proof of an account and its storage
+type AccountProof struct {
+	// Account is the account object or nil if the account does not exist
+	Account *Account
+
+	// Proof are the rlp encoded trie nodes from the state root to the account
+	Proof [][]byte
+
+	// StorageProof are the proofs of the storage slots
+	StorageProof []*StorageProof
+}
+
+// StorageProof is the merkle proof of a storage slot
+type StorageProof struct {
+	// Value is the value of the slot or nil if it is empty
+	Value []byte
+
+	// Proof are the rlp encoded trie nodes from the storage root to the slot
+	Proof [][]byte
+}
+
+// AccessListBackend is an optional interface for backends that trace the
+// accounts and storage slots accessed by a transaction (EIP-2930)
+type AccessListBackend interface {
+	// CreateAccessList executes the transaction and returns the accessed accounts
+	// and slots and the gas used by the transaction with that access list
+	CreateAccessList(tx *ethgo.Transaction, header *ethgo.Block) (*AccessListResult, error)
+}
+
+// AccessListResult is the result of tracing the access list of a transaction
+type AccessListResult struct {
+	// AccessList are the accessed accounts and storage slots
+	AccessList ethgo.AccessList
+
+	// GasUsed is the gas used by the transaction with the access list
+	GasUsed uint64
+
+	// Err is the execution error (i.e. revert) of the transaction if any
+	Err error
+}
+
+// RevertError is the error returned by the backend when the execution reverts
+type RevertError struct {
+	// Data is the revert data returned by the execution
+	Data []byte
+}
+
+// revertSelector is the selector of the Error(string) revert reason
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// Reason returns the decoded Error(string) revert reason if any
+func (r *RevertError) Reason() (string, bool) {
+	data := r.Data
+	if len(data) < 4+64 || !bytes.Equal(data[:4], revertSelector) {
+		return "", false
+	}
+	data = data[4:]
+
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64()+32 > uint64(len(data)) {
+		return "", false
+	}
+	start := offset.Uint64() + 32
+	size := new(big.Int).SetBytes(data[start-32 : start])
+	if !size.IsUint64() || start+size.Uint64() > uint64(len(data)) {
+		return "", false
+	}
+	return string(data[start : start+size.Uint64()]), true
+}
+
+func (r *RevertError) Error() string {
+	if reason, ok := r.Reason(); ok {
+		return "execution reverted: " + reason
+	}
+	return "execution reverted"
+}
+
+// ErrorCode implements the jsonrpc.Error interface
+func (r *RevertError) ErrorCode() int {
+	return 3
+}
+
+// ErrorData implements the jsonrpc.DataError interface
+func (r *RevertError) ErrorData() interface{} {
+	return argBytes(r.Data)
+}
+
 type GetLogsInput struct {
 	From      uint64
 	To        uint64
@@ -103,6 +352,8 @@ type GetLogsInput struct {
 	Topics    [][]ethgo.Hash
 }
 
+// TransactionResult is a transaction and its receipt. The receipt
+// is nil if the transaction is pending in the transaction pool.
 type TransactionResult struct {
 	Transaction *ethgo.Transaction
 	Receipt     *ethgo.Receipt
@@ -123,10 +374,6 @@ func (b *nullBlockchainInterface) GetReceiptsByHash(hash ethgo.Hash) ([]*ethgo.R
 	return nil, nil
 }
 
-func (b *nullBlockchainInterface) EstimateGas(tx *ethgo.Transaction, header *ethgo.Block) (uint64, error) {
-	return 0, nil
-}
-
 func (b *nullBlockchainInterface) Call(tx *ethgo.Transaction, header *ethgo.Block) ([]byte, error) {
 	return nil, nil
 }
@@ -147,10 +394,6 @@ func (b *nullBlockchainInterface) GetHeaderByNumber(block uint64) (*ethgo.Block,
 	return nil, false
 }
 
-func (b *nullBlockchainInterface) GetAvgGasPrice() *big.Int {
-	return nil
-}
-
 func (b *nullBlockchainInterface) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, bool) {
 	return nil, false
 }
@@ -175,10 +418,6 @@ func (b *nullBlockchainInterface) GetAccount(root ethgo.Hash, addr ethgo.Address
 	return nil, false, nil
 }
 
-func (b *nullBlockchainInterface) GetLogs(input *GetLogsInput) ([]*ethgo.Log, error) {
-	return nil, nil
-}
-
 type MockSubscription struct {
 	eventCh chan *Event
 }
@@ -0,0 +1,94 @@
+package jsonrpc
+
+import (
+	"github.com/umbracle/ethgo"
+)
+
+const (
+	// bloomSize is the size in bytes of a logs bloom
+	bloomSize = 256
+
+	// bloomBitLength is the number of bits of a logs bloom
+	bloomBitLength = 8 * bloomSize
+)
+
+// createBloom returns the bloom filter of the addresses and topics of the logs
+func createBloom(logs []*ethgo.Log) []byte {
+	bloom := make([]byte, bloomSize)
+	for _, log := range logs {
+		addToBloom(bloom, log.Address[:])
+		for _, topic := range log.Topics {
+			addToBloom(bloom, topic[:])
+		}
+	}
+	return bloom
+}
+
+func addToBloom(bloom []byte, data []byte) {
+	for _, bit := range bloomBits(data) {
+		bloom[bloomSize-1-bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// bloomBits returns the three bits of the bloom set by the data
+func bloomBits(data []byte) [3]uint {
+	hash := ethgo.Keccak256(data)
+
+	var bits [3]uint
+	for i := 0; i < 3; i++ {
+		bits[i] = (uint(hash[2*i])<<8 | uint(hash[2*i+1])) & (bloomBitLength - 1)
+	}
+	return bits
+}
+
+func bloomHasBit(bloom []byte, bit uint) bool {
+	return bloom[bloomSize-1-bit/8]&(1<<(bit%8)) != 0
+}
+
+// bloomQuery are the bloom bits of the criteria of a log filter. A bloom
+// matches the query if for each criteria one of its items sets all its bits.
+type bloomQuery [][][3]uint
+
+func newBloomQuery(filter *LogFilter) bloomQuery {
+	query := bloomQuery{}
+	if len(filter.Addresses) != 0 {
+		items := [][3]uint{}
+		for _, addr := range filter.Addresses {
+			items = append(items, bloomBits(addr[:]))
+		}
+		query = append(query, items)
+	}
+	for _, topics := range filter.Topics {
+		if len(topics) == 0 {
+			// any topic matches
+			continue
+		}
+		items := [][3]uint{}
+		for _, topic := range topics {
+			items = append(items, bloomBits(topic[:]))
+		}
+		query = append(query, items)
+	}
+	return query
+}
+
+// matchBloom returns whether the logs bloom may include logs that match the query
+func (q bloomQuery) matchBloom(bloom []byte) bool {
+	if len(bloom) != bloomSize {
+		// the bloom is not known
+		return true
+	}
+	for _, items := range q {
+		match := false
+		for _, bits := range items {
+			if bloomHasBit(bloom, bits[0]) && bloomHasBit(bloom, bits[1]) && bloomHasBit(bloom, bits[2]) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,251 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/umbracle/ethgo"
+)
+
+const (
+	// bloomSectionSize is the number of blocks in a section of the bloom index
+	bloomSectionSize = 4096
+
+	// bloomConfirmations is the number of blocks on top of a section
+	// before it is indexed. It prevents indexing blocks that can be reorged.
+	bloomConfirmations = 256
+
+	// bloomIndexInterval is the interval to check for new sections to index
+	bloomIndexInterval = 10 * time.Second
+)
+
+// bloomIndex is an on-disk index of the logs bloom of the blocks. The chain is split
+// in sections and each section is stored in a file with the hash of the last block
+// of the section followed by one bit vector for each bit of the logs bloom. The bit
+// of a block in the vector is set if the bit is set in the logs bloom of the block.
+// Then, the candidate blocks for a query are found by reading only the vectors of
+// the bits of the query instead of the logs bloom of every block in the section.
+type bloomIndex struct {
+	logger *log.Logger
+	dir    string
+	b      blockchainInterface
+	blooms func(block *ethgo.Block) ([]byte, error)
+
+	// nextSection is the first section that is not indexed
+	lock        sync.Mutex
+	nextSection uint64
+
+	closeCh chan struct{}
+}
+
+func newBloomIndex(logger *log.Logger, dir string, b blockchainInterface, blooms func(block *ethgo.Block) ([]byte, error)) *bloomIndex {
+	if logger == nil {
+		logger = log.New(ioutil.Discard, "", log.LstdFlags)
+	}
+	return &bloomIndex{
+		logger:  logger,
+		dir:     dir,
+		b:       b,
+		blooms:  blooms,
+		closeCh: make(chan struct{}),
+	}
+}
+
+// run indexes the sections of the chain as they are confirmed until the index is closed
+func (i *bloomIndex) run() {
+	ticker := time.NewTicker(bloomIndexInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := i.indexSections(); err != nil {
+			i.logger.Printf("[ERROR] failed to index logs bloom: err=%v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-i.closeCh:
+			return
+		}
+	}
+}
+
+// close stops the indexing of the sections
+func (i *bloomIndex) close() {
+	close(i.closeCh)
+}
+
+// indexSections indexes the confirmed sections that are not indexed yet
+func (i *bloomIndex) indexSections() error {
+	head := i.b.Header().Number
+	if head+1 < bloomSectionSize+bloomConfirmations {
+		return nil
+	}
+	sections := (head + 1 - bloomConfirmations) / bloomSectionSize
+
+	i.lock.Lock()
+	section := i.nextSection
+	i.lock.Unlock()
+
+	for ; section < sections; section++ {
+		select {
+		case <-i.closeCh:
+			return nil
+		default:
+		}
+
+		// the sections indexed before a restart are reused
+		if file, ok := i.openSection(section); ok {
+			file.Close()
+		} else if err := i.indexSection(section); err != nil {
+			return err
+		}
+
+		i.lock.Lock()
+		if i.nextSection == section {
+			i.nextSection = section + 1
+		}
+		i.lock.Unlock()
+	}
+	return nil
+}
+
+func (i *bloomIndex) sectionPath(section uint64) string {
+	return filepath.Join(i.dir, fmt.Sprintf("section-%d", section))
+}
+
+// indexSection writes the bit vectors of the section
+func (i *bloomIndex) indexSection(section uint64) error {
+	vectors := make([][]byte, bloomBitLength)
+	for bit := range vectors {
+		vectors[bit] = make([]byte, bloomSectionSize/8)
+	}
+
+	var last *ethgo.Block
+	for indx := uint64(0); indx < bloomSectionSize; indx++ {
+		num := section*bloomSectionSize + indx
+
+		block, ok := i.b.GetBlockByNumber(num, false)
+		if !ok {
+			return fmt.Errorf("block %d not found", num)
+		}
+		bloom, err := i.blooms(block)
+		if err != nil {
+			return err
+		}
+		for bit := uint(0); bit < bloomBitLength; bit++ {
+			if bloomHasBit(bloom, bit) {
+				vectors[bit][indx/8] |= 0x80 >> (indx % 8)
+			}
+		}
+		last = block
+	}
+
+	data := make([]byte, 0, 32+bloomBitLength*bloomSectionSize/8)
+	data = append(data, last.Hash[:]...)
+	for _, vector := range vectors {
+		data = append(data, vector...)
+	}
+
+	// write the section atomically
+	if err := os.MkdirAll(i.dir, 0755); err != nil {
+		return err
+	}
+	path := i.sectionPath(section)
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// openSection opens the file of the section if it is indexed and it
+// belongs to the canonical chain. Otherwise, the file is removed.
+func (i *bloomIndex) openSection(section uint64) (*os.File, bool) {
+	path := i.sectionPath(section)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var hash ethgo.Hash
+	if _, err := file.ReadAt(hash[:], 0); err != nil {
+		file.Close()
+		return nil, false
+	}
+	last, ok := i.b.GetBlockByNumber((section+1)*bloomSectionSize-1, false)
+	if !ok || last.Hash != hash {
+		file.Close()
+		os.Remove(path)
+
+		// index the section again
+		i.lock.Lock()
+		if section < i.nextSection {
+			i.nextSection = section
+		}
+		i.lock.Unlock()
+		return nil, false
+	}
+	return file, true
+}
+
+// match returns the first block of the indexed section that includes the block
+// and a bit vector with the blocks in the section that may match the query
+func (i *bloomIndex) match(query bloomQuery, num uint64) (uint64, []byte, bool) {
+	section := num / bloomSectionSize
+
+	file, ok := i.openSection(section)
+	if !ok {
+		return 0, nil, false
+	}
+	defer file.Close()
+
+	cache := map[uint][]byte{}
+	readVector := func(bit uint) ([]byte, error) {
+		if vector, ok := cache[bit]; ok {
+			return vector, nil
+		}
+		vector := make([]byte, bloomSectionSize/8)
+		if _, err := file.ReadAt(vector, int64(32+bit*bloomSectionSize/8)); err != nil {
+			return nil, err
+		}
+		cache[bit] = vector
+		return vector, nil
+	}
+
+	matches := make([]byte, bloomSectionSize/8)
+	for indx := range matches {
+		matches[indx] = 0xff
+	}
+	for _, items := range query {
+		// one of the items of the criteria has to match
+		criteria := make([]byte, bloomSectionSize/8)
+		for _, bits := range items {
+			item := make([]byte, bloomSectionSize/8)
+			for indx := range item {
+				item[indx] = 0xff
+			}
+			for _, bit := range bits {
+				vector, err := readVector(bit)
+				if err != nil {
+					i.logger.Printf("[ERROR] failed to read logs bloom index: section=%d, err=%v", section, err)
+					return 0, nil, false
+				}
+				for indx := range item {
+					item[indx] &= vector[indx]
+				}
+			}
+			for indx := range criteria {
+				criteria[indx] |= item[indx]
+			}
+		}
+		for indx := range matches {
+			matches[indx] &= criteria[indx]
+		}
+	}
+	return section * bloomSectionSize, matches, true
+}
@@ -0,0 +1,138 @@
+package jsonrpc
+
+import (
+	"context"
+	"math/big"
+)
+
+// Config is the configuration of the eth endpoint
+type Config struct {
+	// FeeHistoryMaxBlocks is the maximum number of blocks
+	// that can be queried in eth_feeHistory
+	FeeHistoryMaxBlocks uint64
+
+	// GasPriceBlocks is the number of blocks sampled by the gas price oracle
+	GasPriceBlocks uint64
+
+	// GasPricePercentile is the percentile of the sampled tips
+	// suggested by the gas price oracle
+	GasPricePercentile uint64
+
+	// GasPriceIgnore is the minimum tip sampled by the gas price oracle
+	GasPriceIgnore *big.Int
+
+	// GasPriceMax is the maximum tip suggested by the gas price oracle
+	GasPriceMax *big.Int
+
+	// LogsMaxBlockRange is the maximum range of blocks queried in
+	// eth_getLogs. Zero means no limit.
+	LogsMaxBlockRange uint64
+
+	// LogsMaxResults is the maximum number of logs returned
+	// by eth_getLogs. Zero means no limit.
+	LogsMaxResults uint64
+
+	// LogIndexDir is the directory of the logs bloom index used to search
+	// logs when the backend does not implement LogsBackend. Empty disables it.
+	LogIndexDir string
+
+	// Signer signs the transactions and messages of eth_sendTransaction and
+	// the eth_sign endpoints. Nil disables them.
+	Signer Signer
+
+	// AllowUnprotectedTxs allows eth_sendRawTransaction to send
+	// transactions without replay protection (EIP-155)
+	AllowUnprotectedTxs bool
+
+	// TxFeeCap is the maximum fee in wei of the transactions sent
+	// with eth_sendRawTransaction. Zero means no limit.
+	TxFeeCap *big.Int
+
+	// Context stops the background tasks of the endpoint (i.e. the filter
+	// manager and the logs bloom index) once it is done. Nil never stops them.
+	Context context.Context
+}
+
+type ConfigOption func(*Config)
+
+// WithFeeHistoryMaxBlocks sets the maximum number of blocks in eth_feeHistory
+func WithFeeHistoryMaxBlocks(num uint64) ConfigOption {
+	return func(c *Config) {
+		c.FeeHistoryMaxBlocks = num
+	}
+}
+
+// WithGasPriceOracle sets the number of blocks and the percentile of
+// the tips used by the gas price oracle
+func WithGasPriceOracle(blocks, percentile uint64) ConfigOption {
+	return func(c *Config) {
+		c.GasPriceBlocks = blocks
+		c.GasPricePercentile = percentile
+	}
+}
+
+// WithGasPriceLimits sets the minimum tip sampled and the maximum tip
+// suggested by the gas price oracle
+func WithGasPriceLimits(ignore, max *big.Int) ConfigOption {
+	return func(c *Config) {
+		c.GasPriceIgnore = ignore
+		c.GasPriceMax = max
+	}
+}
+
+// WithLogsLimits sets the maximum block range and the maximum number of results
+// of eth_getLogs. Zero disables the limit.
+func WithLogsLimits(blockRange, results uint64) ConfigOption {
+	return func(c *Config) {
+		c.LogsMaxBlockRange = blockRange
+		c.LogsMaxResults = results
+	}
+}
+
+// WithLogIndex sets the directory of the logs bloom index
+func WithLogIndex(dir string) ConfigOption {
+	return func(c *Config) {
+		c.LogIndexDir = dir
+	}
+}
+
+// WithSigner sets the signer of the accounts managed by the node
+func WithSigner(signer Signer) ConfigOption {
+	return func(c *Config) {
+		c.Signer = signer
+	}
+}
+
+// WithUnprotectedTxs sets whether transactions without replay protection are allowed
+func WithUnprotectedTxs(allow bool) ConfigOption {
+	return func(c *Config) {
+		c.AllowUnprotectedTxs = allow
+	}
+}
+
+// WithTxFeeCap sets the maximum fee of the transactions sent with eth_sendRawTransaction
+func WithTxFeeCap(cap *big.Int) ConfigOption {
+	return func(c *Config) {
+		c.TxFeeCap = cap
+	}
+}
+
+// WithContext stops the background tasks of the endpoint once the context is done
+func WithContext(ctx context.Context) ConfigOption {
+	return func(c *Config) {
+		c.Context = ctx
+	}
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		FeeHistoryMaxBlocks: 1024,
+		GasPriceBlocks:      20,
+		GasPricePercentile:  60,
+		GasPriceIgnore:      big.NewInt(2),
+		GasPriceMax:         big.NewInt(500 * 1e9),
+		LogsMaxBlockRange:   10000,
+		LogsMaxResults:      10000,
+		TxFeeCap:            big.NewInt(1e18),
+	}
+}
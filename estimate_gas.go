@@ -0,0 +1,84 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+)
+
+// txGas is the gas of a transaction without data
+const txGas = 21000
+
+// estimateGas returns the lowest gas in the [txGas, gasCap] range that
+// executes the transaction without failing. It returns the error of the
+// execution (i.e. the revert reason) if the transaction fails at gasCap.
+func (e *Eth) estimateGas(txn *ethgo.Transaction, header *ethgo.Block, overrides *CallOverrides, gasCap uint64) (uint64, error) {
+	if gasCap < txGas {
+		return 0, fmt.Errorf("gas required exceeds allowance (%d)", gasCap)
+	}
+
+	executable := func(gas uint64) error {
+		txn.Gas = gas
+		_, err := e.call(txn, header, overrides)
+		return err
+	}
+
+	// the transaction has to succeed with the highest gas
+	if err := executable(gasCap); err != nil {
+		if _, ok := err.(*RevertError); ok {
+			return 0, err
+		}
+		return 0, fmt.Errorf("gas required exceeds allowance (%d): %v", gasCap, err)
+	}
+
+	lo, hi := uint64(txGas-1), gasCap
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		if err := executable(mid); err != nil {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	txn.Gas = hi
+
+	return hi, nil
+}
+
+// capGasByBalance returns the maximum gas that the sender can pay
+// for with its balance (or the overridden one) at the fee cap of the transaction
+func (e *Eth) capGasByBalance(txn *ethgo.Transaction, header *ethgo.Block, overrides *CallOverrides, gasCap uint64) (uint64, error) {
+	feeCap := new(big.Int).SetUint64(txn.GasPrice)
+	if txn.Type == ethgo.TransactionDynamicFee {
+		feeCap = bigOrZero(txn.MaxFeePerGas)
+	}
+	if feeCap.Sign() == 0 {
+		return gasCap, nil
+	}
+
+	balance := new(big.Int)
+	if account, ok := overrides.account(txn.From); ok && account.Balance != nil {
+		balance.Set(account.Balance)
+	} else {
+		acc, found, err := e.b.GetAccount(header.StateRoot, txn.From)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			balance.Set(bigOrZero(acc.Balance))
+		}
+	}
+
+	value := bigOrZero(txn.Value)
+	if value.Cmp(balance) > 0 {
+		return 0, fmt.Errorf("insufficient funds for transfer")
+	}
+	allowance := new(big.Int).Sub(balance, value)
+	allowance.Div(allowance, feeCap)
+
+	if allowance.IsUint64() && allowance.Uint64() < gasCap {
+		return allowance.Uint64(), nil
+	}
+	return gasCap, nil
+}
@@ -0,0 +1,157 @@
+package jsonrpc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+)
+
+type mockEstimateStore struct {
+	mockAccountStore
+	required uint64
+	revert   []byte
+}
+
+func (m *mockEstimateStore) Header() *ethgo.Block {
+	return &ethgo.Block{GasLimit: 1000000}
+}
+
+func (m *mockEstimateStore) GetAvgGasPrice() *big.Int {
+	return big.NewInt(1)
+}
+
+func (m *mockEstimateStore) Call(tx *ethgo.Transaction, header *ethgo.Block) ([]byte, error) {
+	if m.revert != nil {
+		return nil, &RevertError{Data: m.revert}
+	}
+	if tx.Gas < m.required {
+		return nil, fmt.Errorf("out of gas")
+	}
+	return nil, nil
+}
+
+func TestEth_EstimateGas(t *testing.T) {
+	b := &mockEstimateStore{required: 53000}
+	b.AddAccount(addr1).Balance(100000)
+
+	eth := NewEth(b)
+
+	estimate := func(arg *txnArgs) (uint64, error) {
+		res, err := eth.EstimateGas(arg, nil, nil, nil)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(res.(argUint64)), nil
+	}
+
+	from := addr1
+	gas, err := estimate(&txnArgs{From: &from, To: &addr2})
+	assert.NoError(t, err)
+	assert.Equal(t, gas, uint64(53000))
+
+	// the gas is capped by the user
+	_, err = estimate(&txnArgs{From: &from, To: &addr2, Gas: argUintPtr(50000)})
+	assert.Error(t, err)
+
+	// the gas is capped by the balance of the sender
+	_, err = estimate(&txnArgs{From: &from, To: &addr2, GasPrice: argBytesPtr([]byte{0x2})})
+	assert.Error(t, err)
+
+	gas, err = estimate(&txnArgs{From: &from, To: &addr2, GasPrice: argBytesPtr([]byte{0x1})})
+	assert.NoError(t, err)
+	assert.Equal(t, gas, uint64(53000))
+
+	// the value is higher than the balance
+	_, err = estimate(&txnArgs{From: &from, To: &addr2, GasPrice: argBytesPtr([]byte{0x1}), Value: argBytesPtr(big.NewInt(200000).Bytes())})
+	assert.Error(t, err)
+
+	// the revert reason is returned
+	b.revert, _ = hex.DecodeString("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6661696c00000000000000000000000000000000000000000000000000000000")
+
+	_, err = estimate(&txnArgs{From: &from, To: &addr2})
+	assert.Equal(t, err.Error(), "execution reverted: fail")
+
+	revertErr, ok := err.(*RevertError)
+	assert.True(t, ok)
+	assert.Equal(t, revertErr.ErrorCode(), 3)
+
+	// the transaction is empty
+	_, err = estimate(nil)
+	assert.EqualError(t, err, "transaction is empty")
+}
+
+type mockGasEstimatorStore struct {
+	mockEstimateStore
+	header *ethgo.Block
+}
+
+func (m *mockGasEstimatorStore) EstimateGas(tx *ethgo.Transaction, header *ethgo.Block) (uint64, error) {
+	m.header = header
+	return 100, nil
+}
+
+func TestEth_EstimateGas_Backend(t *testing.T) {
+	b := &mockGasEstimatorStore{}
+	eth := NewEth(b)
+
+	from := addr1
+	res, err := eth.EstimateGas(&txnArgs{From: &from, To: &addr2}, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, res, argUint64(100))
+
+	// the block parameter is used
+	assert.Equal(t, b.header.GasLimit, uint64(1000000))
+}
+
+func TestRevertError_Reason(t *testing.T) {
+	err := &RevertError{Data: []byte{0x1, 0x2}}
+	assert.Equal(t, err.Error(), "execution reverted")
+	assert.Equal(t, err.ErrorData(), argBytes{0x1, 0x2})
+}
+
+type mockOverrideStore struct {
+	mockEstimateStore
+	overrides *CallOverrides
+}
+
+func (m *mockOverrideStore) CallWithOverrides(tx *ethgo.Transaction, header *ethgo.Block, overrides *CallOverrides) ([]byte, error) {
+	m.overrides = overrides
+	return m.Call(tx, header)
+}
+
+func TestEth_EstimateGas_Overrides(t *testing.T) {
+	b := &mockOverrideStore{}
+	b.required = 53000
+
+	eth := NewEth(b)
+
+	from := addr1
+	arg := func() *txnArgs {
+		return &txnArgs{From: &from, To: &addr2, GasPrice: argBytesPtr([]byte{0x1})}
+	}
+
+	// the sender has no funds
+	_, err := eth.EstimateGas(arg(), nil, nil, nil)
+	assert.Error(t, err)
+
+	// the balance of the sender is overridden
+	balance := argBig(*big.NewInt(100000))
+	state := stateOverrideArgs{
+		addr1: {Balance: &balance},
+	}
+	res, err := eth.EstimateGas(arg(), nil, state, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, res, argUint64(53000))
+	assert.Equal(t, b.overrides.State[addr1].Balance.Uint64(), uint64(100000))
+
+	// the gas limit of the block is overridden
+	_, err = eth.EstimateGas(arg(), nil, state, &blockOverrideArgs{GasLimit: argUintPtr(50000)})
+	assert.Error(t, err)
+}
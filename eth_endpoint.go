@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/umbracle/eth-jsonrpc-server/jsonrpc"
 	"github.com/umbracle/ethgo"
 )
 
@@ -13,40 +14,203 @@ type EthBackend interface {
 
 // Eth is the eth jsonrpc endpoint
 type Eth struct {
-	f *FilterManager
-	b EthBackend
+	config *Config
+	f      *FilterManager
+	b      EthBackend
+
+	feeCache *feeCache
+	oracle   *gasPriceOracle
+	index    *bloomIndex
 }
 
-func NewEth(b EthBackend) *Eth {
+func NewEth(b EthBackend, opts ...ConfigOption) *Eth {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
 	e := &Eth{
-		b: b,
-		f: NewFilterManager(nil, b),
+		config:   config,
+		b:        b,
+		f:        NewFilterManager(nil, b),
+		feeCache: newFeeCache(feeCacheSize),
 	}
+	e.oracle = newGasPriceOracle(config, e.getBlockFees)
 	go e.f.Run()
+
+	if _, ok := b.(LogsBackend); !ok && config.LogIndexDir != "" {
+		e.index = newBloomIndex(nil, config.LogIndexDir, b, e.logsBloom)
+		go e.index.run()
+	}
+	if ctx := config.Context; ctx != nil {
+		go func() {
+			<-ctx.Done()
+			e.close()
+		}()
+	}
 	return e
 }
 
+// close stops the filter manager and the logs bloom index
+func (e *Eth) close() {
+	e.f.Close()
+	if e.index != nil {
+		e.index.close()
+	}
+}
+
 // ChainId returns the chain id of the client
 func (e *Eth) ChainId() (interface{}, error) {
 	return argUintPtr(e.b.ChainID()), nil
 }
 
+// Syncing returns the sync progress of the node or false if it is not syncing
+func (e *Eth) Syncing() (interface{}, error) {
+	sb, ok := e.b.(SyncBackend)
+	if !ok {
+		return false, nil
+	}
+	progress, syncing := sb.SyncStatus()
+	if !syncing {
+		return false, nil
+	}
+	return &rpcSyncProgress{
+		StartingBlock: argUint64(progress.StartingBlock),
+		CurrentBlock:  argUint64(progress.CurrentBlock),
+		HighestBlock:  argUint64(progress.HighestBlock),
+	}, nil
+}
+
+// Accounts returns the accounts owned by the node
+func (e *Eth) Accounts() ([]ethgo.Address, error) {
+	if e.config.Signer == nil {
+		return []ethgo.Address{}, nil
+	}
+	return e.config.Signer.Accounts(), nil
+}
+
+// Coinbase returns the address that receives the mining rewards. The node
+// does not mine so it is always the zero address.
+func (e *Eth) Coinbase() (ethgo.Address, error) {
+	return ethgo.ZeroAddress, nil
+}
+
+// Mining returns whether the node is mining
+func (e *Eth) Mining() (bool, error) {
+	return false, nil
+}
+
+// Hashrate returns the number of hashes per second of the node
+func (e *Eth) Hashrate() (argUint64, error) {
+	return argUint64(0), nil
+}
+
 // GetBlockByNumber returns information about a block by block number
-func (e *Eth) GetBlockByNumber(number BlockNumber, full bool) (*ethgo.Block, error) {
-	header, err := e.getBlockHeaderImpl(number)
+func (e *Eth) GetBlockByNumber(number BlockNumber, full bool) (*rpcBlock, error) {
+	block, ok, err := e.getBlockByNumber(number, full)
 	if err != nil {
 		return nil, err
 	}
-	return header, nil
+	if !ok {
+		// the block does not exists
+		return nil, nil
+	}
+	return e.toRPCBlock(block, full), nil
 }
 
 // GetBlockByHash returns information about a block by hash
-func (e *Eth) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, error) {
+func (e *Eth) GetBlockByHash(hash ethgo.Hash, full bool) (*rpcBlock, error) {
 	block, ok := e.b.GetBlockByHash(hash, full)
 	if !ok {
-		return nil, fmt.Errorf("unable to get block by hash %v", hash)
+		// the block does not exists
+		return nil, nil
+	}
+	return e.toRPCBlock(block, full), nil
+}
+
+// GetBlockTransactionCountByNumber returns the number of transactions in the block
+func (e *Eth) GetBlockTransactionCountByNumber(number BlockNumber) (*argUint64, error) {
+	block, ok, err := e.getBlockByNumber(number, true)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return argUintPtr(txnCount(block)), nil
+}
+
+// GetBlockTransactionCountByHash returns the number of transactions in the block
+func (e *Eth) GetBlockTransactionCountByHash(hash ethgo.Hash) (*argUint64, error) {
+	block, ok := e.b.GetBlockByHash(hash, true)
+	if !ok {
+		return nil, nil
+	}
+	return argUintPtr(txnCount(block)), nil
+}
+
+// GetTransactionByBlockNumberAndIndex returns the transaction at the index of the block
+func (e *Eth) GetTransactionByBlockNumberAndIndex(number BlockNumber, index argUint64) (*rpcTransaction, error) {
+	block, ok, err := e.getBlockByNumber(number, true)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return txnByIndex(block, uint64(index), e.baseFee(block)), nil
+}
+
+// GetTransactionByBlockHashAndIndex returns the transaction at the index of the block
+func (e *Eth) GetTransactionByBlockHashAndIndex(hash ethgo.Hash, index argUint64) (*rpcTransaction, error) {
+	block, ok := e.b.GetBlockByHash(hash, true)
+	if !ok {
+		return nil, nil
+	}
+	return txnByIndex(block, uint64(index), e.baseFee(block)), nil
+}
+
+// GetUncleCountByBlockNumber returns the number of uncles in the block
+func (e *Eth) GetUncleCountByBlockNumber(number BlockNumber) (*argUint64, error) {
+	block, ok, err := e.getBlockByNumber(number, false)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return argUintPtr(uint64(len(block.Uncles))), nil
+}
+
+// GetUncleCountByBlockHash returns the number of uncles in the block
+func (e *Eth) GetUncleCountByBlockHash(hash ethgo.Hash) (*argUint64, error) {
+	block, ok := e.b.GetBlockByHash(hash, false)
+	if !ok {
+		return nil, nil
+	}
+	return argUintPtr(uint64(len(block.Uncles))), nil
+}
+
+// GetUncleByBlockNumberAndIndex returns the uncle at the index of the block
+func (e *Eth) GetUncleByBlockNumberAndIndex(number BlockNumber, index argUint64) (*rpcBlock, error) {
+	block, ok, err := e.getBlockByNumber(number, false)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return e.uncleByIndex(block, uint64(index)), nil
+}
+
+// GetUncleByBlockHashAndIndex returns the uncle at the index of the block
+func (e *Eth) GetUncleByBlockHashAndIndex(hash ethgo.Hash, index argUint64) (*rpcBlock, error) {
+	block, ok := e.b.GetBlockByHash(hash, false)
+	if !ok {
+		return nil, nil
 	}
-	return block, nil
+	return e.uncleByIndex(block, uint64(index)), nil
+}
+
+// GetBlockReceipts returns the receipts of all the transactions in the block
+func (e *Eth) GetBlockReceipts(number BlockNumberOrHash) ([]*rpcReceipt, error) {
+	if number.BlockNumber != nil && *number.BlockNumber == PendingBlockNumber {
+		// the receipts of the pending block are not available
+		number = BlockNumberOrHashWithNumber(LatestBlockNumber)
+	}
+	block, ok, err := e.getBlockByNumberOrHash(number, true)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return e.getBlockReceipts(block)
 }
 
 // BlockNumber returns current block number
@@ -58,48 +222,213 @@ func (e *Eth) BlockNumber() (argUint64, error) {
 	return argUint64(h.Number), nil
 }
 
-// SendRawTransaction sends a raw transaction
+// SendRawTransaction validates the signed transaction and sends it to the
+// transaction pool. It returns the hash of the transaction.
 func (e *Eth) SendRawTransaction(input argBytes) (argBytes, error) {
-	hash, err := e.b.AddTx(input)
+	txn, err := e.decodeRawTxn(input)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := e.b.AddTx(input); err != nil {
+		return nil, err
+	}
+	return argBytes(txn.Hash[:]), nil
+}
+
+// SendRawTransactionConditional sends the signed transaction to the transaction pool
+// if the conditions are met at the current header. The backend has to implement
+// SubmitBackend to check the conditions again when the transaction is included.
+func (e *Eth) SendRawTransactionConditional(input argBytes, args *conditionalArgs) (argBytes, error) {
+	conditions, err := decodeConditions(args)
+	if err != nil {
+		return nil, err
+	}
+	txn, err := e.decodeRawTxn(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.checkConditions(conditions, e.b.Header()); err != nil {
+		return nil, err
+	}
+	if err := e.submitTxn(input, &SubmitOptions{Conditions: conditions}); err != nil {
+		return nil, err
+	}
+	return argBytes(txn.Hash[:]), nil
+}
+
+// SendPrivateTransaction sends the signed transaction to the transaction pool
+// without gossiping it to the peers. The transaction is dropped after the
+// max block number if any.
+func (e *Eth) SendPrivateTransaction(args *privateTxnArgs) (argBytes, error) {
+	if args == nil {
+		return nil, fmt.Errorf("transaction is empty")
+	}
+	txn, err := e.decodeRawTxn(args.Tx)
+	if err != nil {
+		return nil, err
+	}
+	opts := &SubmitOptions{
+		Private: true,
+	}
+	if args.MaxBlockNumber != nil {
+		maxBlockNumber := uint64(*args.MaxBlockNumber)
+		opts.Conditions = &TxConditions{
+			BlockNumberMax: &maxBlockNumber,
+		}
+		if err := e.checkConditions(opts.Conditions, e.b.Header()); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.submitTxn(args.Tx, opts); err != nil {
+		return nil, err
+	}
+	return argBytes(txn.Hash[:]), nil
+}
+
+// SendTransaction signs the transaction with the account of the sender and
+// sends it to the transaction pool
+func (e *Eth) SendTransaction(arg *txnArgs) (argBytes, error) {
+	txn, err := e.signTxnArgs(arg)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := txn.MarshalRLPTo(nil)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := e.b.AddTx(raw)
 	if err != nil {
 		return nil, err
 	}
 	return argBytes(hash[:]), nil
 }
 
+// SignTransaction signs the transaction with the account of the sender without sending it
+func (e *Eth) SignTransaction(arg *txnArgs) (*rpcSignTransactionResult, error) {
+	txn, err := e.signTxnArgs(arg)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := txn.MarshalRLPTo(nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &rpcSignTransactionResult{
+		Raw: argBytes(raw),
+		Tx:  toRPCTransaction(txn, nil, 0, nil),
+	}
+	return res, nil
+}
+
+// Sign signs the message with the account (EIP-191)
+func (e *Eth) Sign(addr ethgo.Address, data argBytes) (argBytes, error) {
+	if e.config.Signer == nil {
+		return nil, fmt.Errorf("signing is not supported")
+	}
+	sig, err := e.config.Signer.SignHash(addr, signMessageHash(data))
+	if err != nil {
+		return nil, err
+	}
+	return toRPCSignature(sig), nil
+}
+
+// SignTypedData_v4 signs the structured data with the account (EIP-712)
+func (e *Eth) SignTypedData_v4(addr ethgo.Address, data *typedData) (argBytes, error) {
+	if e.config.Signer == nil {
+		return nil, fmt.Errorf("signing is not supported")
+	}
+	if data == nil {
+		return nil, fmt.Errorf("typed data is empty")
+	}
+	hash, err := data.hash()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := e.config.Signer.SignHash(addr, hash)
+	if err != nil {
+		return nil, err
+	}
+	return toRPCSignature(sig), nil
+}
+
 // GetTransactionByHash returns a transaction by his hash
-func (e *Eth) GetTransactionByHash(hash ethgo.Hash) (interface{}, error) {
+func (e *Eth) GetTransactionByHash(hash ethgo.Hash) (*rpcTransaction, error) {
 	txn, err := e.b.GetTransactionByHash(hash)
 	if err != nil {
 		// txn not found
 		return nil, err
 	}
-	if txn == nil {
+	if txn == nil || txn.Transaction == nil {
 		return nil, nil
 	}
-	return txn.Transaction, nil
+
+	block, index, ok := e.getTransactionBlock(txn)
+	if ok {
+		return toRPCTransaction(txn.Transaction, block, index, e.baseFee(block)), nil
+	}
+	if txn.Receipt != nil {
+		// use the block context of the receipt
+		block = &ethgo.Block{Hash: txn.Receipt.BlockHash, Number: txn.Receipt.BlockNumber}
+		return toRPCTransaction(txn.Transaction, block, txn.Receipt.TransactionIndex, nil), nil
+	}
+	// the transaction is either pending in the pool or
+	// the block fields are the ones of the transaction
+	return toRPCTransaction(txn.Transaction, nil, 0, nil), nil
 }
 
 // GetTransactionReceipt returns a transaction receipt by his hash
-func (e *Eth) GetTransactionReceipt(hash ethgo.Hash) (interface{}, error) {
+func (e *Eth) GetTransactionReceipt(hash ethgo.Hash) (*rpcReceipt, error) {
 	txn, err := e.b.GetTransactionByHash(hash)
 	if err != nil {
 		// txn not found
 		return nil, err
 	}
-	if txn == nil {
+	if txn == nil || txn.Transaction == nil {
 		return nil, nil
 	}
 	if txn.Receipt == nil {
 		return nil, nil
 	}
-	return txn.Receipt, nil
+	receipt := txn.Receipt
+
+	block, index, ok := e.getTransactionBlock(txn)
+	if !ok {
+		// use the block context of the receipt
+		logIndex := uint64(0)
+		if len(receipt.Logs) != 0 {
+			logIndex = receipt.Logs[0].LogIndex
+		}
+		return toRPCReceipt(receipt, txn.Transaction, receipt.BlockHash, receipt.BlockNumber, receipt.TransactionIndex, logIndex, nil), nil
+	}
+
+	// the receipts of the previous transactions are only loaded to index
+	// the logs in the block or if the backend does not set the cumulative gas
+	logIndex, cumulativeGasUsed := uint64(0), uint64(0)
+	if len(receipt.Logs) != 0 || receipt.CumulativeGasUsed == 0 {
+		receipts, err := e.b.GetReceiptsByHash(block.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(receipts)) <= index {
+			return nil, fmt.Errorf("block %d has %d receipts but the transaction index is %d", block.Number, len(receipts), index)
+		}
+		for _, r := range receipts[:index] {
+			logIndex += uint64(len(r.Logs))
+			cumulativeGasUsed += r.GasUsed
+		}
+	}
+
+	res := toRPCReceipt(receipt, txn.Transaction, block.Hash, block.Number, index, logIndex, e.baseFee(block))
+	if receipt.CumulativeGasUsed == 0 {
+		res.CumulativeGasUsed = argUint64(cumulativeGasUsed + receipt.GasUsed)
+	}
+	return res, nil
 }
 
 // GetStorageAt returns the contract storage at the index position
-func (e *Eth) GetStorageAt(address ethgo.Address, index ethgo.Hash, number BlockNumber) (interface{}, error) {
+func (e *Eth) GetStorageAt(address ethgo.Address, index ethgo.Hash, number BlockNumberOrHash) (interface{}, error) {
 	// Fetch the requested header
-	header, err := e.getBlockHeaderImpl(number)
+	header, err := e.getBlockHeaderByNumberOrHash(number)
 	if err != nil {
 		return nil, err
 	}
@@ -115,24 +444,28 @@ func (e *Eth) GetStorageAt(address ethgo.Address, index ethgo.Hash, number Block
 	return argBytesPtr(result), nil
 }
 
-// GasPrice returns the average gas price based on the last x blocks
+// GasPrice returns the suggested gas price for legacy transactions
 func (e *Eth) GasPrice() (interface{}, error) {
-	return argBigPtr(e.b.GetAvgGasPrice()), nil
+	return argBigPtr(e.suggestGasPrice()), nil
 }
 
 // Call executes a smart contract call using the transaction object data
-func (e *Eth) Call(arg *txnArgs, number BlockNumber) (interface{}, error) {
-	transaction, err := e.decodeTxn(arg)
+func (e *Eth) Call(arg *txnArgs, number BlockNumberOrHash, stateOverrides stateOverrideArgs, blockOverrides *blockOverrideArgs) (interface{}, error) {
+	overrides, err := e.decodeOverrides(stateOverrides, blockOverrides)
 	if err != nil {
 		return nil, err
 	}
 	// Fetch the requested header
-	header, err := e.getBlockHeaderImpl(number)
+	header, err := e.getBlockHeaderByNumberOrHash(number)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := e.decodeTxn(arg, header)
 	if err != nil {
 		return nil, err
 	}
 
-	retValue, err := e.b.Call(transaction, header)
+	retValue, err := e.call(transaction, header, overrides)
 	if err != nil {
 		return nil, err
 	}
@@ -140,73 +473,186 @@ func (e *Eth) Call(arg *txnArgs, number BlockNumber) (interface{}, error) {
 }
 
 // EstimateGas estimates the gas needed to execute a transaction
-func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error) {
-	transaction, err := e.decodeTxn(arg)
+func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumberOrHash, stateOverrides stateOverrideArgs, blockOverrides *blockOverrideArgs) (interface{}, error) {
+	if arg == nil {
+		return nil, fmt.Errorf("transaction is empty")
+	}
+	overrides, err := e.decodeOverrides(stateOverrides, blockOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	number := BlockNumberOrHashWithNumber(LatestBlockNumber)
+	if rawNum != nil {
+		number = *rawNum
+	}
+	header, err := e.getBlockHeaderByNumberOrHash(number)
 	if err != nil {
 		return nil, err
 	}
-	gas, err := e.b.EstimateGas(transaction, nil)
+
+	// the gas is only capped if it is set by the user
+	hasGas := arg.Gas != nil
+
+	transaction, err := e.decodeTxn(arg, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if eb, ok := e.b.(GasEstimatorBackend); ok && overrides == nil {
+		gas, err := eb.EstimateGas(transaction, header)
+		if err != nil {
+			return nil, err
+		}
+		return argUint64(gas), nil
+	}
+
+	gasCap := header.GasLimit
+	if overrides != nil && overrides.Block != nil && overrides.Block.GasLimit != nil {
+		gasCap = *overrides.Block.GasLimit
+	}
+	if hasGas {
+		gasCap = transaction.Gas
+	}
+	if gasCap, err = e.capGasByBalance(transaction, header, overrides, gasCap); err != nil {
+		return nil, err
+	}
+	gas, err := e.estimateGas(transaction, header, overrides, gasCap)
 	if err != nil {
 		return nil, err
 	}
 	return argUint64(gas), nil
 }
 
+// CreateAccessList returns the access list of the transaction and the gas it uses with it (EIP-2930)
+func (e *Eth) CreateAccessList(arg *txnArgs, number BlockNumberOrHash) (*rpcAccessListResult, error) {
+	ab, ok := e.b.(AccessListBackend)
+	if !ok {
+		return nil, fmt.Errorf("access list creation is not supported")
+	}
+
+	header, err := e.getBlockHeaderByNumberOrHash(number)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := e.decodeTxn(arg, header)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := ab.CreateAccessList(transaction, header)
+	if err != nil {
+		return nil, err
+	}
+	res := &rpcAccessListResult{
+		AccessList: toRPCAccessList(result.AccessList),
+		GasUsed:    argUint64(result.GasUsed),
+	}
+	if result.Err != nil {
+		res.Error = result.Err.Error()
+	}
+	return res, nil
+}
+
 // GetLogs returns an array of logs matching the filter options
 func (e *Eth) GetLogs(filterOptions *LogFilter) ([]*ethgo.Log, error) {
 	head := e.b.Header()
 
 	if filterOptions.BlockHash != nil {
-		receipts, err := e.b.GetReceiptsByHash(*filterOptions.BlockHash)
+		block, ok := e.b.GetBlockByHash(*filterOptions.BlockHash, false)
+		if !ok {
+			return nil, fmt.Errorf("unknown block %s", *filterOptions.BlockHash)
+		}
+		result, err := e.blockLogs(block, filterOptions)
 		if err != nil {
 			return nil, err
 		}
-		var result []*ethgo.Log
-		for _, receipt := range receipts {
-			for _, log := range receipt.Logs {
-				if filterOptions.Match(log) {
-					result = append(result, log)
-				}
-			}
+		if max := e.config.LogsMaxResults; max != 0 && uint64(len(result)) > max {
+			return nil, fmt.Errorf("query returned more than %d results", max)
 		}
 		return result, nil
 	}
 
-	resolveNum := func(num BlockNumber) uint64 {
-		if num == PendingBlockNumber || num == EarliestBlockNumber {
-			num = LatestBlockNumber
-		}
-		if num == LatestBlockNumber {
-			return head.Number
+	resolveNum := func(num BlockNumber) (uint64, error) {
+		if num == PendingBlockNumber {
+			// the logs of the pending block are not available
+			return head.Number, nil
 		}
-		return uint64(num)
+		return e.getBlockNumber(num)
 	}
 
-	from := resolveNum(filterOptions.fromBlock)
-	to := resolveNum(filterOptions.toBlock)
+	from, err := resolveNum(filterOptions.fromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := resolveNum(filterOptions.toBlock)
+	if err != nil {
+		return nil, err
+	}
 
 	if to < from {
 		return nil, fmt.Errorf("incorrect range")
 	}
-
-	input := &GetLogsInput{
-		From:      from,
-		To:        to,
-		Addresses: filterOptions.Addresses,
-		Topics:    filterOptions.Topics,
+	if max := e.config.LogsMaxBlockRange; max != 0 && to-from >= max {
+		return nil, &logsLimitError{
+			msg:  fmt.Sprintf("exceed maximum block range: %d", max),
+			from: from,
+			to:   from + max - 1,
+		}
 	}
-	logs, err := e.b.GetLogs(input)
+
+	logs, err := e.getLogs(filterOptions, from, to)
 	if err != nil {
 		return nil, err
 	}
+	if max := e.config.LogsMaxResults; max != 0 && uint64(len(logs)) > max {
+		// suggest the range that ends before the block of the first log over the limit
+		last := logs[max].BlockNumber
+		if last > from {
+			last--
+		}
+		if last < from || last > to {
+			last = from
+		}
+		return nil, &logsLimitError{
+			msg:  fmt.Sprintf("query returned more than %d results", max),
+			from: from,
+			to:   last,
+		}
+	}
 	return logs, nil
 }
 
+// logsLimitError is the error returned when a logs query exceeds the
+// limits. It suggests a narrower block range for the query.
+type logsLimitError struct {
+	msg  string
+	from uint64
+	to   uint64
+}
+
+func (l *logsLimitError) Error() string {
+	return fmt.Sprintf("%s. Try with this block range [%#x, %#x].", l.msg, l.from, l.to)
+}
+
+// ErrorCode implements the jsonrpc.Error interface
+func (l *logsLimitError) ErrorCode() int {
+	return -32005
+}
+
+// ErrorData implements the jsonrpc.DataError interface
+func (l *logsLimitError) ErrorData() interface{} {
+	return map[string]argUint64{
+		"from": argUint64(l.from),
+		"to":   argUint64(l.to),
+	}
+}
+
 var zero = big.NewInt(0)
 
 // GetBalance returns the account's balance at the referenced block
-func (e *Eth) GetBalance(address ethgo.Address, number BlockNumber) (*argBig, error) {
-	header, err := e.getBlockHeaderImpl(number)
+func (e *Eth) GetBalance(address ethgo.Address, number BlockNumberOrHash) (*argBig, error) {
+	header, err := e.getBlockHeaderByNumberOrHash(number)
 	if err != nil {
 		return nil, err
 	}
@@ -222,7 +668,7 @@ func (e *Eth) GetBalance(address ethgo.Address, number BlockNumber) (*argBig, er
 }
 
 // GetTransactionCount returns account nonce
-func (e *Eth) GetTransactionCount(address ethgo.Address, number BlockNumber) (interface{}, error) {
+func (e *Eth) GetTransactionCount(address ethgo.Address, number BlockNumberOrHash) (interface{}, error) {
 	nonce, err := e.getNextNonce(address, number)
 	if err != nil {
 		return nil, err
@@ -231,8 +677,8 @@ func (e *Eth) GetTransactionCount(address ethgo.Address, number BlockNumber) (in
 }
 
 // GetCode returns account code at given block number
-func (e *Eth) GetCode(address ethgo.Address, number BlockNumber) (argBytes, error) {
-	header, err := e.getBlockHeaderImpl(number)
+func (e *Eth) GetCode(address ethgo.Address, number BlockNumberOrHash) (argBytes, error) {
+	header, err := e.getBlockHeaderByNumberOrHash(number)
 	if err != nil {
 		return nil, err
 	}
@@ -246,6 +692,35 @@ func (e *Eth) GetCode(address ethgo.Address, number BlockNumber) (argBytes, erro
 	return e.b.GetCode(ethgo.BytesToHash(acc.CodeHash))
 }
 
+// GetProof returns the merkle proof of the account and its storage slots (EIP-1186)
+func (e *Eth) GetProof(address ethgo.Address, storageKeys []argBytes, number BlockNumberOrHash) (*rpcAccountProof, error) {
+	pb, ok := e.b.(ProofBackend)
+	if !ok {
+		return nil, fmt.Errorf("account proofs are not supported")
+	}
+
+	keys := []ethgo.Hash{}
+	for _, key := range storageKeys {
+		if len(key) > 32 {
+			return nil, fmt.Errorf("storage key too long: %d bytes", len(key))
+		}
+		keys = append(keys, ethgo.BytesToHash(key))
+	}
+
+	header, err := e.getBlockHeaderByNumberOrHash(number)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := pb.GetProof(header.StateRoot, address, keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(proof.StorageProof) != len(keys) {
+		return nil, fmt.Errorf("expected %d storage proofs but found %d", len(keys), len(proof.StorageProof))
+	}
+	return toRPCAccountProof(address, keys, proof)
+}
+
 // NewFilter creates a filter object, based on filter options, to notify when the state changes (logs).
 func (e *Eth) NewFilter(filter *LogFilter) (interface{}, error) {
 	return e.f.NewLogFilter(filter, nil), nil
@@ -261,48 +736,242 @@ func (e *Eth) GetFilterChanges(id string) (interface{}, error) {
 	return e.f.GetFilterChanges(id)
 }
 
+// GetFilterLogs returns all the logs matching the log filter with given ID
+func (e *Eth) GetFilterLogs(id string) ([]*ethgo.Log, error) {
+	filter, err := e.f.GetLogFilter(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.GetLogs(filter)
+}
+
 // UninstallFilter uninstalls a filter with given ID
 func (e *Eth) UninstallFilter(id string) (bool, error) {
 	ok := e.f.Uninstall(id)
 	return ok, nil
 }
 
+// Subscribe creates a subscription for new heads or logs over a websocket
+func (e *Eth) Subscribe(stream jsonrpc.Stream, name string, filter *LogFilter) (string, error) {
+	switch name {
+	case "newHeads":
+		return e.f.NewBlockFilter(stream), nil
+
+	case "logs":
+		if filter == nil {
+			filter = &LogFilter{fromBlock: LatestBlockNumber, toBlock: LatestBlockNumber}
+		}
+		return e.f.NewLogFilter(filter, stream), nil
+
+	default:
+		return "", fmt.Errorf("subscription %s not supported", name)
+	}
+}
+
 // Unsubscribe uninstalls a filter in a websocket
 func (e *Eth) Unsubscribe(id string) (bool, error) {
 	ok := e.f.Uninstall(id)
 	return ok, nil
 }
 
-func (e *Eth) getBlockHeaderImpl(number BlockNumber) (*ethgo.Block, error) {
+// getBlockByNumber returns the block referenced by the number or tag. It returns
+// the pending block if the backend builds one or the latest block otherwise.
+func (e *Eth) getBlockByNumber(number BlockNumber, full bool) (*ethgo.Block, bool, error) {
+	if number == PendingBlockNumber {
+		if block, ok := e.getPendingBlock(); ok {
+			return block, true, nil
+		}
+		number = LatestBlockNumber
+	}
+	num, err := e.getBlockNumber(number)
+	if err != nil {
+		return nil, false, err
+	}
+	block, ok := e.b.GetBlockByNumber(num, full)
+	return block, ok, nil
+}
+
+// getBlockByNumberOrHash returns the block referenced either by number or hash
+func (e *Eth) getBlockByNumberOrHash(number BlockNumberOrHash, full bool) (*ethgo.Block, bool, error) {
+	if number.BlockHash == nil {
+		if number.BlockNumber == nil {
+			return e.getBlockByNumber(LatestBlockNumber, full)
+		}
+		return e.getBlockByNumber(*number.BlockNumber, full)
+	}
+
+	hash := *number.BlockHash
+	block, ok := e.b.GetBlockByHash(hash, full)
+	if !ok {
+		return nil, false, nil
+	}
+	if number.RequireCanonical {
+		canonical, ok := e.b.GetBlockByNumber(block.Number, false)
+		if !ok || canonical.Hash != hash {
+			return nil, false, fmt.Errorf("hash %s is not currently canonical", hash)
+		}
+	}
+	return block, true, nil
+}
+
+// getBlockReceipts returns the receipts of the block with the
+// block, transaction and log fields derived from the block
+func (e *Eth) getBlockReceipts(block *ethgo.Block) ([]*rpcReceipt, error) {
+	receipts, err := e.b.GetReceiptsByHash(block.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return toRPCReceipts(block, e.baseFee(block), receipts)
+}
+
+// getTransactionBlock returns the header of the block that includes the
+// mined transaction and the index of the transaction in the block
+func (e *Eth) getTransactionBlock(txn *TransactionResult) (*ethgo.Block, uint64, bool) {
+	blockHash := txn.Transaction.BlockHash
+	if txn.Receipt != nil && txn.Receipt.BlockHash != ethgo.ZeroHash {
+		blockHash = txn.Receipt.BlockHash
+	}
+	if blockHash == ethgo.ZeroHash {
+		return nil, 0, false
+	}
+	block, ok := e.b.GetBlockByHash(blockHash, false)
+	if !ok {
+		return nil, 0, false
+	}
+	for indx, hash := range txnHashes(block) {
+		if hash == txn.Transaction.Hash {
+			return block, uint64(indx), true
+		}
+	}
+	return nil, 0, false
+}
+
+// uncleByIndex returns the uncle at the index of the block or nil if
+// the index is out of range or the uncle is not found
+func (e *Eth) uncleByIndex(block *ethgo.Block, index uint64) *rpcBlock {
+	if index >= uint64(len(block.Uncles)) {
+		return nil
+	}
+	uncle, ok := e.b.GetBlockByHash(block.Uncles[index], false)
+	if !ok {
+		return nil
+	}
+	return e.toRPCBlock(uncle, false)
+}
+
+// getPendingBlock returns the pending block if the backend builds one
+func (e *Eth) getPendingBlock() (*ethgo.Block, bool) {
+	pb, ok := e.b.(PendingBackend)
+	if !ok {
+		return nil, false
+	}
+	return pb.PendingBlock()
+}
+
+// getFinalityHeader returns the header for the safe and finalized tags
+func (e *Eth) getFinalityHeader(number BlockNumber) (*ethgo.Block, error) {
+	fb, ok := e.b.(FinalityBackend)
+	if !ok {
+		return nil, fmt.Errorf("fetching the %s header is not supported", number)
+	}
+
+	var header *ethgo.Block
+	if number == SafeBlockNumber {
+		header, ok = fb.SafeHeader()
+	} else {
+		header, ok = fb.FinalizedHeader()
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s block not found", number)
+	}
+	return header, nil
+}
+
+// getBlockNumber resolves the block number of a block tag
+func (e *Eth) getBlockNumber(number BlockNumber) (uint64, error) {
 	switch number {
 	case LatestBlockNumber:
-		return e.b.Header(), nil
+		return e.b.Header().Number, nil
 
 	case EarliestBlockNumber:
-		return nil, fmt.Errorf("fetching the earliest header is not supported")
+		return 0, nil
 
 	case PendingBlockNumber:
-		return nil, fmt.Errorf("fetching the pending header is not supported")
+		if block, ok := e.getPendingBlock(); ok {
+			return block.Number, nil
+		}
+		return e.b.Header().Number, nil
+
+	case SafeBlockNumber, FinalizedBlockNumber:
+		header, err := e.getFinalityHeader(number)
+		if err != nil {
+			return 0, err
+		}
+		return header.Number, nil
 
 	default:
-		// Convert the block number from hex to uint64
-		header, ok := e.b.GetBlockByNumber(uint64(number), false)
+		if number < 0 {
+			return 0, fmt.Errorf("invalid block number %d", number)
+		}
+		return uint64(number), nil
+	}
+}
+
+func (e *Eth) getBlockHeaderImpl(number BlockNumber) (*ethgo.Block, error) {
+	switch number {
+	case LatestBlockNumber:
+		return e.b.Header(), nil
+
+	case PendingBlockNumber:
+		// use the latest header if there is no pending block
+		if block, ok := e.getPendingBlock(); ok {
+			return block, nil
+		}
+		return e.b.Header(), nil
+
+	case SafeBlockNumber, FinalizedBlockNumber:
+		return e.getFinalityHeader(number)
+
+	default:
+		num, err := e.getBlockNumber(number)
+		if err != nil {
+			return nil, err
+		}
+		header, ok := e.b.GetBlockByNumber(num, false)
 		if !ok {
-			return nil, fmt.Errorf("error fetching block number %d header", uint64(number))
+			return nil, fmt.Errorf("error fetching block number %d header", num)
 		}
 		return header, nil
 	}
 }
 
-func (e *Eth) getNextNonce(address ethgo.Address, number BlockNumber) (uint64, error) {
-	if number == PendingBlockNumber {
+// getBlockHeaderByNumberOrHash returns the header referenced either by number or hash
+func (e *Eth) getBlockHeaderByNumberOrHash(number BlockNumberOrHash) (*ethgo.Block, error) {
+	if number.BlockHash == nil {
+		if number.BlockNumber == nil {
+			return e.getBlockHeaderImpl(LatestBlockNumber)
+		}
+		return e.getBlockHeaderImpl(*number.BlockNumber)
+	}
+
+	header, ok, err := e.getBlockByNumberOrHash(number, false)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("header for hash %s not found", *number.BlockHash)
+	}
+	return header, nil
+}
+
+func (e *Eth) getNextNonce(address ethgo.Address, number BlockNumberOrHash) (uint64, error) {
+	if number.BlockNumber != nil && *number.BlockNumber == PendingBlockNumber {
 		res, ok := e.b.GetPendingNonce(address)
 		if ok {
 			return res, nil
 		}
-		number = LatestBlockNumber
 	}
-	header, err := e.getBlockHeaderImpl(number)
+	header, err := e.getBlockHeaderByNumberOrHash(number)
 	if err != nil {
 		return 0, err
 	}
@@ -316,7 +985,194 @@ func (e *Eth) getNextNonce(address ethgo.Address, number BlockNumber) (uint64, e
 	return acc.Nonce, nil
 }
 
-func (e *Eth) decodeTxn(arg *txnArgs) (*ethgo.Transaction, error) {
+// toRPCBlock converts the block to its jsonrpc representation with the
+// base fee and header fields from the backend
+func (e *Eth) toRPCBlock(block *ethgo.Block, full bool) *rpcBlock {
+	fields := &HeaderFields{}
+	if hb, ok := e.b.(HeaderFieldsBackend); ok {
+		if f := hb.HeaderFields(block); f != nil {
+			*fields = *f
+		}
+	}
+	if len(fields.LogsBloom) == 0 {
+		if bb, ok := e.b.(BloomBackend); ok {
+			fields.LogsBloom = bb.LogsBloom(block)
+		}
+	}
+	return toRPCBlock(block, full, e.baseFee(block), fields)
+}
+
+// baseFee returns the base fee of the block or nil if the
+// backend does not support EIP-1559
+func (e *Eth) baseFee(block *ethgo.Block) *big.Int {
+	if fb, ok := e.b.(FeeMarketBackend); ok {
+		return fb.BaseFee(block)
+	}
+	return nil
+}
+
+// suggestGasPrice returns the suggested gas price for legacy transactions, either
+// from the backend or as the tip of the gas price oracle plus the base fee
+func (e *Eth) suggestGasPrice() *big.Int {
+	if gb, ok := e.b.(GasPriceBackend); ok {
+		return bigOrZero(gb.GetAvgGasPrice())
+	}
+
+	head := e.b.Header()
+	tip := e.oracle.suggestTipCap(head)
+	if baseFee := e.baseFee(head); baseFee != nil {
+		tip.Add(tip, baseFee)
+	}
+	return tip
+}
+
+// suggestGasTipCap returns the suggested priority fee for dynamic fee transactions
+func (e *Eth) suggestGasTipCap(baseFee *big.Int) *big.Int {
+	gb, ok := e.b.(GasPriceBackend)
+	if !ok {
+		return e.oracle.suggestTipCap(e.b.Header())
+	}
+
+	price := gb.GetAvgGasPrice()
+	if price == nil {
+		return new(big.Int)
+	}
+	tip := new(big.Int).Sub(price, bigOrZero(baseFee))
+	if tip.Sign() < 0 {
+		return new(big.Int)
+	}
+	return tip
+}
+
+// call executes the transaction on the backend with the overrides if any
+func (e *Eth) call(txn *ethgo.Transaction, header *ethgo.Block, overrides *CallOverrides) ([]byte, error) {
+	if overrides == nil {
+		return e.b.Call(txn, header)
+	}
+	ob, ok := e.b.(OverrideBackend)
+	if !ok {
+		return nil, fmt.Errorf("state and block overrides are not supported")
+	}
+	return ob.CallWithOverrides(txn, header, overrides)
+}
+
+// decodeOverrides validates the state and block overrides of the call endpoints.
+// It returns nil if there are no overrides.
+func (e *Eth) decodeOverrides(state stateOverrideArgs, block *blockOverrideArgs) (*CallOverrides, error) {
+	if state == nil && block == nil {
+		return nil, nil
+	}
+	if _, ok := e.b.(OverrideBackend); !ok {
+		return nil, fmt.Errorf("state and block overrides are not supported")
+	}
+
+	overrides := &CallOverrides{}
+	if state != nil {
+		overrides.State = map[ethgo.Address]*AccountOverride{}
+		for addr, arg := range state {
+			if arg == nil {
+				continue
+			}
+			if arg.State != nil && arg.StateDiff != nil {
+				return nil, fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr)
+			}
+			account := &AccountOverride{
+				State:     arg.State,
+				StateDiff: arg.StateDiff,
+			}
+			if arg.Nonce != nil {
+				nonce := uint64(*arg.Nonce)
+				account.Nonce = &nonce
+			}
+			if arg.Code != nil {
+				account.Code = []byte(*arg.Code)
+			}
+			if arg.Balance != nil {
+				account.Balance = new(big.Int).Set((*big.Int)(arg.Balance))
+			}
+			overrides.State[addr] = account
+		}
+	}
+	if block != nil {
+		overrides.Block = &BlockOverride{
+			Coinbase: block.Coinbase,
+		}
+		if block.Number != nil {
+			num := uint64(*block.Number)
+			overrides.Block.Number = &num
+		}
+		if block.Time != nil {
+			time := uint64(*block.Time)
+			overrides.Block.Time = &time
+		}
+		if block.GasLimit != nil {
+			gasLimit := uint64(*block.GasLimit)
+			overrides.Block.GasLimit = &gasLimit
+		}
+		if block.BaseFee != nil {
+			overrides.Block.BaseFee = new(big.Int).Set((*big.Int)(block.BaseFee))
+		}
+	}
+	return overrides, nil
+}
+
+// submitTxn adds the transaction to the pool with the submission options
+func (e *Eth) submitTxn(raw []byte, opts *SubmitOptions) error {
+	sb, ok := e.b.(SubmitBackend)
+	if !ok {
+		return fmt.Errorf("transaction submission options are not supported")
+	}
+	_, err := sb.AddTxWithOptions(raw, opts)
+	return err
+}
+
+// signTxnArgs fills the defaults of the transaction and signs it with the account of the sender
+func (e *Eth) signTxnArgs(arg *txnArgs) (*ethgo.Transaction, error) {
+	if arg == nil {
+		return nil, fmt.Errorf("transaction is empty")
+	}
+	if e.config.Signer == nil {
+		return nil, fmt.Errorf("signing is not supported")
+	}
+	if arg.From == nil {
+		return nil, fmt.Errorf("from is empty")
+	}
+	if arg.Nonce == nil {
+		// include the transactions of the sender in the pool
+		nonce, err := e.getNextNonce(*arg.From, BlockNumberOrHashWithNumber(PendingBlockNumber))
+		if err != nil {
+			return nil, err
+		}
+		arg.Nonce = argUintPtr(nonce)
+	}
+	if arg.Gas == nil {
+		// estimate with a copy since the defaults are set in the arguments
+		estimateArg := *arg
+		gas, err := e.EstimateGas(&estimateArg, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		arg.Gas = argUintPtr(uint64(gas.(argUint64)))
+	}
+	if err := e.setFeeDefaults(arg); err != nil {
+		return nil, err
+	}
+
+	txn, err := e.decodeTxn(arg, e.b.Header())
+	if err != nil {
+		return nil, err
+	}
+	if err := signTxn(e.config.Signer, txn, e.b.ChainID()); err != nil {
+		return nil, err
+	}
+	return txn, nil
+}
+
+// decodeTxn decodes the transaction arguments to be executed on top of the header
+func (e *Eth) decodeTxn(arg *txnArgs, header *ethgo.Block) (*ethgo.Transaction, error) {
+	if arg == nil {
+		return nil, fmt.Errorf("transaction is empty")
+	}
 	// set default values
 	if arg.From == nil {
 		return nil, fmt.Errorf("from is empty")
@@ -326,7 +1182,7 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*ethgo.Transaction, error) {
 	}
 	if arg.Nonce == nil {
 		// get nonce from the pool
-		nonce, err := e.getNextNonce(*arg.From, LatestBlockNumber)
+		nonce, err := e.getNextNonce(*arg.From, BlockNumberOrHashWithNumber(LatestBlockNumber))
 		if err != nil {
 			return nil, err
 		}
@@ -335,9 +1191,21 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*ethgo.Transaction, error) {
 	if arg.Value == nil {
 		arg.Value = argBytesPtr([]byte{})
 	}
-	if arg.GasPrice == nil {
-		// use the suggested gas price
-		arg.GasPrice = argBytesPtr(e.b.GetAvgGasPrice().Bytes())
+
+	chainID := e.b.ChainID()
+	if arg.ChainID != nil && uint64(*arg.ChainID) != chainID {
+		return nil, fmt.Errorf("chainId does not match node's (have=%d, want=%d)", uint64(*arg.ChainID), chainID)
+	}
+
+	baseFee := e.baseFee(header)
+	if arg.Type == nil && arg.GasPrice == nil && arg.MaxFeePerGas == nil && arg.MaxPriorityFeePerGas == nil {
+		// without fees the transaction is executed as a legacy
+		// transaction with a zero gas price like geth does
+		baseFee = nil
+	}
+	typ, err := e.txnType(arg, baseFee)
+	if err != nil {
+		return nil, err
 	}
 
 	var input []byte
@@ -356,14 +1224,14 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*ethgo.Transaction, error) {
 	}
 
 	if arg.Gas == nil {
-		// TODO
-		arg.Gas = argUintPtr(1000000)
+		// use the gas limit of the block
+		arg.Gas = argUintPtr(header.GasLimit)
 	}
 
 	txn := &ethgo.Transaction{
-		From: *arg.From,
-		Gas:  uint64(*arg.Gas),
-		// GasPrice: new(big.Int).SetBytes(*arg.GasPrice),
+		Type:  typ,
+		From:  *arg.From,
+		Gas:   uint64(*arg.Gas),
 		Value: new(big.Int).SetBytes(*arg.Value),
 		Input: input,
 		Nonce: uint64(*arg.Nonce),
@@ -372,6 +1240,39 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*ethgo.Transaction, error) {
 		txn.To = arg.To
 	}
 
+	// the missing fees are zero
+	if typ == ethgo.TransactionDynamicFee {
+		txn.MaxFeePerGas = new(big.Int)
+		if arg.MaxFeePerGas != nil {
+			txn.MaxFeePerGas.SetBytes(*arg.MaxFeePerGas)
+		}
+		txn.MaxPriorityFeePerGas = new(big.Int)
+		if arg.MaxPriorityFeePerGas != nil {
+			txn.MaxPriorityFeePerGas.SetBytes(*arg.MaxPriorityFeePerGas)
+		}
+		if arg.MaxFeePerGas != nil && txn.MaxFeePerGas.Cmp(txn.MaxPriorityFeePerGas) < 0 {
+			return nil, fmt.Errorf("maxFeePerGas (%s) < maxPriorityFeePerGas (%s)", txn.MaxFeePerGas, txn.MaxPriorityFeePerGas)
+		}
+	} else {
+		gasPrice := new(big.Int)
+		if arg.GasPrice != nil {
+			gasPrice.SetBytes(*arg.GasPrice)
+		}
+		if !gasPrice.IsUint64() {
+			return nil, fmt.Errorf("gasPrice too high")
+		}
+		txn.GasPrice = gasPrice.Uint64()
+	}
+	if typ != ethgo.TransactionLegacy {
+		txn.ChainID = new(big.Int).SetUint64(chainID)
+		for _, entry := range arg.AccessList {
+			txn.AccessList = append(txn.AccessList, ethgo.AccessEntry{
+				Address: entry.Address,
+				Storage: entry.StorageKeys,
+			})
+		}
+	}
+
 	hash, err := txn.GetHash()
 	if err != nil {
 		return nil, err
@@ -380,3 +1281,76 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*ethgo.Transaction, error) {
 
 	return txn, nil
 }
+
+// txnType validates the fee fields of the transaction arguments and
+// returns the type of the transaction
+func (e *Eth) txnType(arg *txnArgs, baseFee *big.Int) (ethgo.TransactionType, error) {
+	isDynamic := arg.MaxFeePerGas != nil || arg.MaxPriorityFeePerGas != nil
+	if arg.GasPrice != nil && isDynamic {
+		return 0, fmt.Errorf("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+
+	var typ ethgo.TransactionType
+	if arg.Type != nil {
+		typ = ethgo.TransactionType(*arg.Type)
+		switch typ {
+		case ethgo.TransactionLegacy, ethgo.TransactionAccessList:
+			if isDynamic {
+				return 0, fmt.Errorf("maxFeePerGas and maxPriorityFeePerGas not supported for transaction type %d", typ)
+			}
+		case ethgo.TransactionDynamicFee:
+			if arg.GasPrice != nil {
+				return 0, fmt.Errorf("gasPrice not supported for transaction type %d", typ)
+			}
+			if baseFee == nil {
+				return 0, fmt.Errorf("transaction type %d not supported before london", typ)
+			}
+		default:
+			return 0, fmt.Errorf("transaction type %d not supported", typ)
+		}
+	} else if isDynamic || (arg.GasPrice == nil && baseFee != nil) {
+		if baseFee == nil {
+			return 0, fmt.Errorf("maxFeePerGas and maxPriorityFeePerGas not supported before london")
+		}
+		typ = ethgo.TransactionDynamicFee
+	} else if arg.AccessList != nil {
+		typ = ethgo.TransactionAccessList
+	} else {
+		typ = ethgo.TransactionLegacy
+	}
+	if typ == ethgo.TransactionLegacy && arg.AccessList != nil {
+		return 0, fmt.Errorf("accessList not supported for legacy transactions")
+	}
+	return typ, nil
+}
+
+// setFeeDefaults fills the missing fee fields of the transaction arguments
+// with the suggested fees. It is only used to send or sign transactions, the
+// calls and the gas estimation leave the missing fees as zero.
+func (e *Eth) setFeeDefaults(arg *txnArgs) error {
+	baseFee := e.baseFee(e.b.Header())
+
+	typ, err := e.txnType(arg, baseFee)
+	if err != nil {
+		return err
+	}
+
+	if typ != ethgo.TransactionDynamicFee {
+		if arg.GasPrice == nil {
+			// use the suggested gas price
+			arg.GasPrice = argBytesPtr(e.suggestGasPrice().Bytes())
+		}
+		return nil
+	}
+
+	if arg.MaxPriorityFeePerGas == nil {
+		arg.MaxPriorityFeePerGas = argBytesPtr(e.suggestGasTipCap(baseFee).Bytes())
+	}
+	if arg.MaxFeePerGas == nil {
+		// leave room for the base fee to double
+		tip := new(big.Int).SetBytes(*arg.MaxPriorityFeePerGas)
+		maxFee := new(big.Int).Add(tip, new(big.Int).Mul(baseFee, big.NewInt(2)))
+		arg.MaxFeePerGas = argBytesPtr(maxFee.Bytes())
+	}
+	return nil
+}
@@ -149,15 +149,22 @@ func TestEth_Block_GetBlockByNumber(t *testing.T) {
 
 	eth := NewEth(b)
 	getBlockByNumber := func(num BlockNumber) bool {
-		_, err := eth.GetBlockByNumber(num, false)
-		return err == nil
+		block, err := eth.GetBlockByNumber(num, false)
+		return err == nil && block != nil
 	}
 
 	// tag is latest block
 	assert.True(t, getBlockByNumber(LatestBlockNumber))
 
-	// earliest header is not supported
-	assert.False(t, getBlockByNumber(EarliestBlockNumber))
+	// earliest is the genesis block
+	assert.True(t, getBlockByNumber(EarliestBlockNumber))
+
+	// pending is the latest block if the backend does not build one
+	assert.True(t, getBlockByNumber(PendingBlockNumber))
+
+	// safe and finalized require a finality backend
+	assert.False(t, getBlockByNumber(SafeBlockNumber))
+	assert.False(t, getBlockByNumber(FinalizedBlockNumber))
 
 	// block number is negative
 	assert.False(t, getBlockByNumber(BlockNumber(-50)))
@@ -170,6 +177,120 @@ func TestEth_Block_GetBlockByNumber(t *testing.T) {
 
 	// block number is too big
 	assert.False(t, getBlockByNumber(BlockNumber(50)))
+
+	// an unknown block is null
+	block, err := eth.GetBlockByNumber(BlockNumber(50), false)
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+}
+
+func TestEth_Block_GetBlockByNumberFull(t *testing.T) {
+	txn := &ethgo.Transaction{
+		Hash:  hash2,
+		Value: big.NewInt(1),
+	}
+
+	b := &mockBlockStore{}
+	b.add(&ethgo.Block{
+		Number:       1,
+		Hash:         hash1,
+		Transactions: []*ethgo.Transaction{txn},
+	})
+
+	eth := NewEth(b)
+
+	// only the hashes
+	block, err := eth.GetBlockByNumber(LatestBlockNumber, false)
+	assert.NoError(t, err)
+	assert.Equal(t, block.Transactions, []interface{}{hash2})
+
+	// full transactions include the block fields
+	block, err = eth.GetBlockByNumber(LatestBlockNumber, true)
+	assert.NoError(t, err)
+
+	res := block.Transactions[0].(*rpcTransaction)
+	assert.Equal(t, res.Hash, hash2)
+	assert.Equal(t, *res.BlockHash, hash1)
+	assert.Equal(t, res.BlockNumber.Uint64(), uint64(1))
+	assert.Equal(t, res.TransactionIndex.Uint64(), uint64(0))
+}
+
+type mockFinalityStore struct {
+	mockBlockStore
+	pending *ethgo.Block
+}
+
+func (m *mockFinalityStore) SafeHeader() (*ethgo.Block, bool) {
+	return m.GetBlockByNumber(uint64(len(m.blocks)-2), false)
+}
+
+func (m *mockFinalityStore) FinalizedHeader() (*ethgo.Block, bool) {
+	return m.GetBlockByNumber(uint64(len(m.blocks)-3), false)
+}
+
+func (m *mockFinalityStore) PendingBlock() (*ethgo.Block, bool) {
+	return m.pending, m.pending != nil
+}
+
+func TestEth_Block_Tags(t *testing.T) {
+	b := &mockFinalityStore{}
+	for i := 0; i < 10; i++ {
+		b.add(&ethgo.Block{
+			Number: uint64(i),
+		})
+	}
+	b.pending = &ethgo.Block{Number: 10}
+
+	eth := NewEth(b)
+
+	cases := []struct {
+		tag BlockNumber
+		num uint64
+	}{
+		{EarliestBlockNumber, 0},
+		{FinalizedBlockNumber, 7},
+		{SafeBlockNumber, 8},
+		{LatestBlockNumber, 9},
+		{PendingBlockNumber, 10},
+	}
+	for _, c := range cases {
+		block, err := eth.GetBlockByNumber(c.tag, false)
+		assert.NoError(t, err)
+		assert.Equal(t, block.Number.Uint64(), c.num)
+
+		header, err := eth.getBlockHeaderImpl(c.tag)
+		assert.NoError(t, err)
+		assert.Equal(t, header.Number, c.num)
+	}
+}
+
+func TestEth_Block_NumberOrHash(t *testing.T) {
+	b := &mockBlockStore{}
+	b.add(&ethgo.Block{Number: 0, Hash: hash3})
+	b.add(&ethgo.Block{Number: 1, Hash: hash1})
+
+	// block not in the canonical chain
+	b.add(&ethgo.Block{Number: 1, Hash: hash2})
+
+	eth := NewEth(b)
+
+	header, err := eth.getBlockHeaderByNumberOrHash(BlockNumberOrHash{})
+	assert.NoError(t, err)
+	assert.Equal(t, header.Hash, hash2)
+
+	header, err = eth.getBlockHeaderByNumberOrHash(BlockNumberOrHashWithNumber(0))
+	assert.NoError(t, err)
+	assert.Equal(t, header.Hash, hash3)
+
+	header, err = eth.getBlockHeaderByNumberOrHash(BlockNumberOrHashWithHash(hash2, false))
+	assert.NoError(t, err)
+	assert.Equal(t, header.Hash, hash2)
+
+	_, err = eth.getBlockHeaderByNumberOrHash(BlockNumberOrHashWithHash(hash2, true))
+	assert.Error(t, err)
+
+	_, err = eth.getBlockHeaderByNumberOrHash(BlockNumberOrHashWithHash(hash1, true))
+	assert.NoError(t, err)
 }
 
 func TestEth_Block_GetBlockByHash(t *testing.T) {
@@ -180,11 +301,48 @@ func TestEth_Block_GetBlockByHash(t *testing.T) {
 
 	eth := NewEth(b)
 
-	_, err := eth.GetBlockByHash(hash1, false)
+	block, err := eth.GetBlockByHash(hash1, false)
 	assert.NoError(t, err)
+	assert.NotNil(t, block)
 
-	_, err = eth.GetBlockByHash(hash2, false)
-	assert.Error(t, err)
+	// the header fields unknown to the backend are zero
+	assert.Equal(t, make(argBytes, bloomSize), block.LogsBloom)
+	assert.Equal(t, make(argBytes, 8), block.Nonce)
+	assert.Equal(t, ethgo.Hash{}, block.MixHash)
+
+	block, err = eth.GetBlockByHash(hash2, false)
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+}
+
+type mockHeaderFieldsStore struct {
+	mockBlockStore
+}
+
+func (m *mockHeaderFieldsStore) HeaderFields(block *ethgo.Block) *HeaderFields {
+	return &HeaderFields{
+		MixHash:         hash2,
+		Nonce:           [8]byte{0x1},
+		Size:            100,
+		TotalDifficulty: big.NewInt(10),
+	}
+}
+
+func TestEth_Block_HeaderFields(t *testing.T) {
+	b := &mockHeaderFieldsStore{}
+	b.add(&ethgo.Block{
+		Hash: hash1,
+	})
+
+	eth := NewEth(b)
+
+	block, err := eth.GetBlockByHash(hash1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, hash2, block.MixHash)
+	assert.Equal(t, argBytes{0x1, 0, 0, 0, 0, 0, 0, 0}, block.Nonce)
+	assert.Equal(t, argUint64(100), block.Size)
+	assert.Equal(t, *argBigPtr(big.NewInt(10)), block.TotalDifficulty)
+	assert.Len(t, block.LogsBloom, bloomSize)
 }
 
 func TestEth_Block_BlockNumber(t *testing.T) {
@@ -204,6 +362,7 @@ type mockStoreLogs struct {
 	nullBlockchainInterface
 	receipts map[ethgo.Hash][]*ethgo.Receipt
 	input    *GetLogsInput
+	logs     []*ethgo.Log
 }
 
 func (m *mockStoreLogs) addReceipt(hash ethgo.Hash, receipts []*ethgo.Receipt) {
@@ -222,9 +381,21 @@ func (m *mockStoreLogs) GetReceiptsByHash(hash ethgo.Hash) ([]*ethgo.Receipt, er
 	return nil, nil
 }
 
+func (m *mockStoreLogs) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, bool) {
+	receipts, ok := m.receipts[hash]
+	if !ok {
+		return nil, false
+	}
+	block := &ethgo.Block{Hash: hash}
+	for indx := range receipts {
+		block.TransactionsHashes = append(block.TransactionsHashes, ethgo.Hash{byte(indx + 1)})
+	}
+	return block, true
+}
+
 func (m *mockStoreLogs) GetLogs(input *GetLogsInput) ([]*ethgo.Log, error) {
 	m.input = input
-	return nil, nil
+	return m.logs, nil
 }
 
 func TestEth_Logs_BlockHash(t *testing.T) {
@@ -257,9 +428,53 @@ func TestEth_Logs_BlockHash(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, logs[0].Address, addr1)
 
-	logs, err = eth.GetLogs(&LogFilter{BlockHash: &hash3})
+	// the transaction fields are taken from the block
+	assert.Equal(t, ethgo.Hash{0x1}, logs[0].TransactionHash)
+	assert.Equal(t, uint64(0), logs[0].TransactionIndex)
+
+	// unknown block hash
+	_, err = eth.GetLogs(&LogFilter{BlockHash: &hash3})
+	assert.Error(t, err)
+}
+
+func TestEth_Logs_Limits(t *testing.T) {
+	b := &mockStoreLogs{}
+	b.addReceipt(hash1, []*ethgo.Receipt{
+		{Logs: []*ethgo.Log{{}, {}, {}}},
+	})
+
+	eth := NewEth(b, WithLogsLimits(10, 2))
+
+	_, err := eth.GetLogs(&LogFilter{fromBlock: 10, toBlock: 19})
+	assert.NoError(t, err)
+
+	// the block range is too wide
+	_, err = eth.GetLogs(&LogFilter{fromBlock: 10, toBlock: 20})
+	assert.Equal(t, err.Error(), "exceed maximum block range: 10. Try with this block range [0xa, 0x13].")
+
+	// too many results
+	b.logs = []*ethgo.Log{
+		{BlockNumber: 10},
+		{BlockNumber: 12},
+		{BlockNumber: 14},
+	}
+	_, err = eth.GetLogs(&LogFilter{fromBlock: 10, toBlock: 19})
+	assert.Equal(t, err.Error(), "query returned more than 2 results. Try with this block range [0xa, 0xd].")
+
+	limitErr, ok := err.(*logsLimitError)
+	assert.True(t, ok)
+	assert.Equal(t, limitErr.ErrorCode(), -32005)
+
+	_, err = eth.GetLogs(&LogFilter{BlockHash: &hash1})
+	assert.Error(t, err)
+
+	// the range is limited by default
+	_, err = NewEth(b).GetLogs(&LogFilter{fromBlock: 0, toBlock: 10000})
+	assert.Error(t, err)
+
+	// zero disables the limits
+	_, err = NewEth(b, WithLogsLimits(0, 0)).GetLogs(&LogFilter{fromBlock: 0, toBlock: 10000})
 	assert.NoError(t, err)
-	assert.Empty(t, logs)
 }
 
 func TestEth_Logs_BlockRange(t *testing.T) {
@@ -269,6 +484,41 @@ func TestEth_Logs_BlockRange(t *testing.T) {
 
 	_, err := eth.GetLogs(&LogFilter{fromBlock: 10, toBlock: 15})
 	assert.NoError(t, err)
+
+	// earliest is the genesis block
+	_, err = eth.GetLogs(&LogFilter{fromBlock: EarliestBlockNumber, toBlock: 15})
+	assert.NoError(t, err)
+	assert.Equal(t, b.input.From, uint64(0))
+
+	// the backend does not track the finalized block
+	_, err = eth.GetLogs(&LogFilter{fromBlock: EarliestBlockNumber, toBlock: FinalizedBlockNumber})
+	assert.Error(t, err)
+}
+
+func TestEth_GetFilterLogs(t *testing.T) {
+	b := &mockStoreLogs{}
+
+	eth := NewEth(b)
+
+	id, err := eth.NewFilter(&LogFilter{fromBlock: 10, toBlock: 15, Addresses: []ethgo.Address{addr1}})
+	assert.NoError(t, err)
+
+	_, err = eth.GetFilterLogs(id.(string))
+	assert.NoError(t, err)
+	assert.Equal(t, b.input.From, uint64(10))
+	assert.Equal(t, b.input.To, uint64(15))
+	assert.Equal(t, b.input.Addresses, []ethgo.Address{addr1})
+
+	// block filters do not have logs
+	id, err = eth.NewBlockFilter()
+	assert.NoError(t, err)
+
+	_, err = eth.GetFilterLogs(id.(string))
+	assert.Error(t, err)
+
+	// unknown filter
+	_, err = eth.GetFilterLogs("1")
+	assert.Error(t, err)
 }
 
 var (
@@ -283,7 +533,7 @@ func TestEth_State_GetBalance(t *testing.T) {
 
 	eth := NewEth(store)
 
-	balance, err := eth.GetBalance(addr0, LatestBlockNumber)
+	balance, err := eth.GetBalance(addr0, BlockNumberOrHashWithNumber(LatestBlockNumber))
 	assert.NoError(t, err)
 	assert.Equal(t, balance, argBigPtr(big.NewInt(100)))
 }
@@ -296,7 +546,7 @@ func TestEth_State_GetTransactionCount(t *testing.T) {
 
 	eth := NewEth(store)
 
-	balance, err := eth.GetTransactionCount(addr0, LatestBlockNumber)
+	balance, err := eth.GetTransactionCount(addr0, BlockNumberOrHashWithNumber(LatestBlockNumber))
 	assert.NoError(t, err)
 	assert.Equal(t, balance, argUintPtr(100))
 }
@@ -311,7 +561,7 @@ func TestEth_State_GetCode(t *testing.T) {
 	eth := NewEth(store)
 
 	// get code of known account
-	code, err := eth.GetCode(addr0, LatestBlockNumber)
+	code, err := eth.GetCode(addr0, BlockNumberOrHashWithNumber(LatestBlockNumber))
 	assert.NoError(t, err)
 	assert.Equal(t, code.Bytes(), code0)
 }
@@ -324,7 +574,7 @@ func TestEth_State_GetStorageAt(t *testing.T) {
 
 	eth := NewEth(store)
 
-	res, err := eth.GetStorageAt(acct0.address, hash1, LatestBlockNumber)
+	res, err := eth.GetStorageAt(acct0.address, hash1, BlockNumberOrHashWithNumber(LatestBlockNumber))
 	assert.NoError(t, err)
 	assert.Equal(t, res, argBytesPtr(hash1[:]))
 }
@@ -345,11 +595,622 @@ func (m *mockStoreTxn) GetNonce(addr ethgo.Address) (uint64, bool) {
 }
 
 func TestEth_TxnPool_SendRawTransaction(t *testing.T) {
-	b := &mockStoreTxn{}
+	b := &mockRawTxnStore{}
+	eth := NewEth(b)
+
+	raw := signedRawTxn(t, &ethgo.Transaction{To: &addr1, Gas: 21000, GasPrice: 1}, 5)
+
+	hash, err := eth.SendRawTransaction(argBytes(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, b.txn, raw)
+
+	// the hash is computed from the raw transaction
+	assert.Equal(t, hash.Bytes(), ethgo.Keccak256(raw))
+
+	// the transaction is not valid rlp
+	_, err = eth.SendRawTransaction(argBytes([]byte{0x1}))
+	assert.Error(t, err)
+}
+
+type mockFeeStore struct {
+	nullBlockchainInterface
+	baseFee *big.Int
+}
+
+func (m *mockFeeStore) ChainID() uint64 {
+	return 10
+}
+
+func (m *mockFeeStore) BaseFee(block *ethgo.Block) *big.Int {
+	return m.baseFee
+}
+
+func (m *mockFeeStore) GetAvgGasPrice() *big.Int {
+	return big.NewInt(150)
+}
+
+func TestEth_DecodeTxn_Fees(t *testing.T) {
+	b := &mockFeeStore{}
+	eth := NewEth(b)
+
+	from := addr1
+	bytesPtr := func(i int64) *argBytes {
+		return argBytesPtr(big.NewInt(i).Bytes())
+	}
+
+	// decodeWithDefaults decodes the arguments with the suggested fees
+	decodeWithDefaults := func(arg *txnArgs) (*ethgo.Transaction, error) {
+		if err := eth.setFeeDefaults(arg); err != nil {
+			return nil, err
+		}
+		return eth.decodeTxn(arg, b.Header())
+	}
+
+	// legacy transaction before london
+	txn, err := decodeWithDefaults(&txnArgs{From: &from, To: &addr2})
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Type, ethgo.TransactionLegacy)
+	assert.Equal(t, txn.GasPrice, uint64(150))
+
+	// the calls do not set a gas price
+	txn, err = eth.decodeTxn(&txnArgs{From: &from, To: &addr2}, b.Header())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), txn.GasPrice)
+
+	// dynamic fee transaction is not supported before london
+	_, err = eth.decodeTxn(&txnArgs{From: &from, To: &addr2, MaxFeePerGas: bytesPtr(10)}, b.Header())
+	assert.Error(t, err)
+
+	b.baseFee = big.NewInt(100)
+
+	// dynamic fee transaction with default values
+	txn, err = decodeWithDefaults(&txnArgs{From: &from, To: &addr2})
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Type, ethgo.TransactionDynamicFee)
+	assert.Equal(t, txn.ChainID.Uint64(), uint64(10))
+	assert.Equal(t, txn.MaxPriorityFeePerGas.Uint64(), uint64(50))
+	assert.Equal(t, txn.MaxFeePerGas.Uint64(), uint64(250))
+
+	// the calls without fees are legacy transactions with a zero gas price
+	txn, err = eth.decodeTxn(&txnArgs{From: &from, To: &addr2}, b.Header())
+	assert.NoError(t, err)
+	assert.Equal(t, ethgo.TransactionLegacy, txn.Type)
+	assert.Equal(t, uint64(0), txn.GasPrice)
+
+	// the calls leave the missing fees as zero
+	txn, err = eth.decodeTxn(&txnArgs{From: &from, To: &addr2, MaxPriorityFeePerGas: bytesPtr(10)}, b.Header())
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Type, ethgo.TransactionDynamicFee)
+	assert.Equal(t, uint64(10), txn.MaxPriorityFeePerGas.Uint64())
+	assert.Equal(t, uint64(0), txn.MaxFeePerGas.Uint64())
+
+	// max fee lower than the priority fee with the defaults
+	_, err = decodeWithDefaults(&txnArgs{From: &from, To: &addr2, MaxPriorityFeePerGas: bytesPtr(10), MaxFeePerGas: bytesPtr(5)})
+	assert.Error(t, err)
+
+	// gas price selects a legacy transaction
+	txn, err = eth.decodeTxn(&txnArgs{From: &from, To: &addr2, GasPrice: bytesPtr(10)}, b.Header())
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Type, ethgo.TransactionLegacy)
+	assert.Equal(t, txn.GasPrice, uint64(10))
+
+	// access list selects an access list transaction
+	txn, err = eth.decodeTxn(&txnArgs{From: &from, To: &addr2, GasPrice: bytesPtr(10), AccessList: []rpcAccessEntry{{Address: addr1}}}, b.Header())
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Type, ethgo.TransactionAccessList)
+	assert.Equal(t, txn.AccessList[0].Address, addr1)
+
+	// gas price and dynamic fees are mutually exclusive
+	_, err = eth.decodeTxn(&txnArgs{From: &from, To: &addr2, GasPrice: bytesPtr(10), MaxFeePerGas: bytesPtr(10)}, b.Header())
+	assert.Error(t, err)
+
+	// max fee lower than the priority fee
+	_, err = eth.decodeTxn(&txnArgs{From: &from, To: &addr2, MaxFeePerGas: bytesPtr(10), MaxPriorityFeePerGas: bytesPtr(20)}, b.Header())
+	assert.Error(t, err)
+
+	// wrong chain id
+	_, err = eth.decodeTxn(&txnArgs{From: &from, To: &addr2, ChainID: argUintPtr(1)}, b.Header())
+	assert.Error(t, err)
+}
+
+func TestEth_Call_Overrides(t *testing.T) {
+	from := addr1
+	state := stateOverrideArgs{
+		addr2: {
+			Nonce: argUintPtr(1),
+			Code:  argBytesPtr([]byte{0x1}),
+		},
+	}
+
+	// the backend does not support overrides
+	eth := NewEth(&mockEstimateStore{})
+
+	_, err := eth.Call(&txnArgs{From: &from, To: &addr2}, BlockNumberOrHash{}, state, nil)
+	assert.Error(t, err)
+
+	_, err = eth.Call(&txnArgs{From: &from, To: &addr2}, BlockNumberOrHash{}, nil, nil)
+	assert.NoError(t, err)
+
+	b := &mockOverrideStore{}
+	eth = NewEth(b)
+
+	_, err = eth.Call(&txnArgs{From: &from, To: &addr2}, BlockNumberOrHash{}, state, &blockOverrideArgs{Number: argUintPtr(10)})
+	assert.NoError(t, err)
+
+	account := b.overrides.State[addr2]
+	assert.Equal(t, *account.Nonce, uint64(1))
+	assert.Equal(t, account.Code, []byte{0x1})
+	assert.Equal(t, *b.overrides.Block.Number, uint64(10))
+
+	// state and stateDiff are mutually exclusive
+	state[addr2].State = map[ethgo.Hash]ethgo.Hash{}
+	state[addr2].StateDiff = map[ethgo.Hash]ethgo.Hash{}
+
+	_, err = eth.Call(&txnArgs{From: &from, To: &addr2}, BlockNumberOrHash{}, state, nil)
+	assert.Error(t, err)
+}
+
+type mockProofStore struct {
+	mockAccountStore
+}
+
+func (m *mockProofStore) GetProof(root ethgo.Hash, addr ethgo.Address, slots []ethgo.Hash) (*AccountProof, error) {
+	proof := &AccountProof{
+		Proof: [][]byte{{0x1}, {0x2}},
+	}
+	acct, ok := m.accounts[addr]
+	if ok {
+		proof.Account = acct.account
+	}
+	for _, slot := range slots {
+		storage := &StorageProof{Proof: [][]byte{{0x3}}}
+		if ok {
+			if val, found := acct.storage[slot]; found {
+				storage.Value = val[:]
+			}
+		}
+		proof.StorageProof = append(proof.StorageProof, storage)
+	}
+	return proof, nil
+}
+
+func TestEth_GetProof(t *testing.T) {
+	store := &mockProofStore{}
+	acct := store.AddAccount(addr0)
+	acct.Balance(100)
+	acct.Nonce(2)
+	acct.Storage(hash1, hash2)
+
+	eth := NewEth(store)
+
+	res, err := eth.GetProof(addr0, []argBytes{hash1[:], {0x3}}, BlockNumberOrHash{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, res.Nonce, argUint64(2))
+	assert.Equal(t, (*big.Int)(&res.Balance).Uint64(), uint64(100))
+	assert.Equal(t, res.CodeHash, emptyCodeHash)
+	assert.Equal(t, res.StorageHash, emptyRoot)
+	assert.Equal(t, res.AccountProof, []argBytes{{0x1}, {0x2}})
+
+	assert.Len(t, res.StorageProof, 2)
+	assert.Equal(t, res.StorageProof[0].Key, hash1)
+	assert.Equal(t, (*big.Int)(&res.StorageProof[0].Value).Bytes(), new(big.Int).SetBytes(hash2[:]).Bytes())
+	assert.Equal(t, res.StorageProof[1].Key, ethgo.BytesToHash([]byte{0x3}))
+	assert.Equal(t, (*big.Int)(&res.StorageProof[1].Value).Uint64(), uint64(0))
+
+	// the account does not exist
+	res, err = eth.GetProof(addr1, nil, BlockNumberOrHash{})
+	assert.NoError(t, err)
+	assert.Equal(t, res.Nonce, argUint64(0))
+	assert.Equal(t, res.StorageProof, []*rpcStorageProof{})
+
+	// the storage key is too long
+	_, err = eth.GetProof(addr0, []argBytes{make([]byte, 33)}, BlockNumberOrHash{})
+	assert.Error(t, err)
+
+	// the backend does not return the proof of a storage key
+	_, err = toRPCAccountProof(addr0, []ethgo.Hash{hash1}, &AccountProof{StorageProof: []*StorageProof{nil}})
+	assert.EqualError(t, err, fmt.Sprintf("storage proof for key %s not found", hash1))
+
+	// the backend does not support proofs
+	_, err = NewEth(&mockAccountStore{}).GetProof(addr0, nil, BlockNumberOrHash{})
+	assert.Error(t, err)
+}
+
+type mockAccessListStore struct {
+	mockEstimateStore
+	txn *ethgo.Transaction
+}
+
+func (m *mockAccessListStore) CreateAccessList(tx *ethgo.Transaction, header *ethgo.Block) (*AccessListResult, error) {
+	m.txn = tx
+	res := &AccessListResult{
+		AccessList: ethgo.AccessList{
+			{Address: addr2, Storage: []ethgo.Hash{hash1}},
+			{Address: addr1},
+		},
+		GasUsed: 30000,
+	}
+	if m.revert != nil {
+		res.Err = &RevertError{}
+	}
+	return res, nil
+}
+
+func TestEth_CreateAccessList(t *testing.T) {
+	b := &mockAccessListStore{}
+	eth := NewEth(b)
+
+	from := addr1
+	res, err := eth.CreateAccessList(&txnArgs{From: &from, To: &addr2, Nonce: argUintPtr(1)}, BlockNumberOrHash{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, res.GasUsed, argUint64(30000))
+	assert.Equal(t, res.AccessList, []rpcAccessEntry{
+		{Address: addr2, StorageKeys: []ethgo.Hash{hash1}},
+		{Address: addr1, StorageKeys: []ethgo.Hash{}},
+	})
+	assert.Empty(t, res.Error)
+	assert.Equal(t, b.txn.Nonce, uint64(1))
+
+	// the execution error is returned with the access list
+	b.revert = []byte{}
+
+	res, err = eth.CreateAccessList(&txnArgs{From: &from, To: &addr2}, BlockNumberOrHash{})
+	assert.NoError(t, err)
+	assert.Equal(t, res.Error, "execution reverted")
+
+	// the transaction is empty
+	_, err = eth.CreateAccessList(nil, BlockNumberOrHash{})
+	assert.EqualError(t, err, "transaction is empty")
+
+	// the backend does not support access lists
+	_, err = NewEth(&mockEstimateStore{}).CreateAccessList(&txnArgs{From: &from, To: &addr2}, BlockNumberOrHash{})
+	assert.Error(t, err)
+}
+
+func TestEth_Block_IndexLookups(t *testing.T) {
+	uncle := &ethgo.Block{Number: 1, Hash: hash2}
+
+	b := &mockBlockStore{}
+	b.add(&ethgo.Block{
+		Number: 0,
+		Hash:   hash1,
+		Transactions: []*ethgo.Transaction{
+			{Hash: ethgo.Hash{0x1}},
+			{Hash: ethgo.Hash{0x2}},
+		},
+		Uncles: []ethgo.Hash{uncle.Hash, ethgo.Hash{0x3}},
+	})
+	b.add(uncle)
+
 	eth := NewEth(b)
 
-	hash, err := eth.SendRawTransaction(argBytes([]byte{0x1}))
+	count, err := eth.GetBlockTransactionCountByNumber(BlockNumber(0))
+	assert.NoError(t, err)
+	assert.Equal(t, *count, argUint64(2))
+
+	count, err = eth.GetBlockTransactionCountByHash(hash1)
 	assert.NoError(t, err)
+	assert.Equal(t, *count, argUint64(2))
 
-	assert.Equal(t, hash.Bytes(), ethgo.Hash{0x1}.Bytes())
+	count, err = eth.GetUncleCountByBlockNumber(BlockNumber(0))
+	assert.NoError(t, err)
+	assert.Equal(t, *count, argUint64(2))
+
+	count, err = eth.GetUncleCountByBlockHash(hash1)
+	assert.NoError(t, err)
+	assert.Equal(t, *count, argUint64(2))
+
+	txn, err := eth.GetTransactionByBlockNumberAndIndex(BlockNumber(0), argUint64(1))
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Hash, ethgo.Hash{0x2})
+	assert.Equal(t, *txn.BlockHash, hash1)
+	assert.Equal(t, *txn.TransactionIndex, argUint64(1))
+
+	txn, err = eth.GetTransactionByBlockHashAndIndex(hash1, argUint64(0))
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Hash, ethgo.Hash{0x1})
+
+	block, err := eth.GetUncleByBlockHashAndIndex(hash1, argUint64(0))
+	assert.NoError(t, err)
+	assert.Equal(t, block.Hash, uncle.Hash)
+
+	block, err = eth.GetUncleByBlockNumberAndIndex(BlockNumber(0), argUint64(0))
+	assert.NoError(t, err)
+	assert.Equal(t, block.Hash, uncle.Hash)
+
+	// out of range indexes and unknown blocks return null
+	txn, err = eth.GetTransactionByBlockNumberAndIndex(BlockNumber(0), argUint64(2))
+	assert.NoError(t, err)
+	assert.Nil(t, txn)
+
+	txn, err = eth.GetTransactionByBlockHashAndIndex(hash2, argUint64(0))
+	assert.NoError(t, err)
+	assert.Nil(t, txn)
+
+	block, err = eth.GetUncleByBlockHashAndIndex(hash1, argUint64(2))
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+
+	// the uncle is not known by the backend
+	block, err = eth.GetUncleByBlockHashAndIndex(hash1, argUint64(1))
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+
+	count, err = eth.GetBlockTransactionCountByNumber(BlockNumber(5))
+	assert.NoError(t, err)
+	assert.Nil(t, count)
+
+	count, err = eth.GetUncleCountByBlockHash(ethgo.Hash{0x5})
+	assert.NoError(t, err)
+	assert.Nil(t, count)
+}
+
+func TestEth_GetBlockReceipts(t *testing.T) {
+	b := &mockFeeHistoryStore{baseFee: big.NewInt(100)}
+	b.addBlock(0)
+
+	block := &ethgo.Block{
+		Number: 1,
+		Hash:   hash1,
+		Transactions: []*ethgo.Transaction{
+			{
+				Hash:     ethgo.Hash{0x1},
+				From:     addr1,
+				To:       &addr2,
+				GasPrice: 150,
+			},
+			{
+				Type:                 ethgo.TransactionDynamicFee,
+				Hash:                 ethgo.Hash{0x2},
+				From:                 addr1,
+				MaxFeePerGas:         big.NewInt(300),
+				MaxPriorityFeePerGas: big.NewInt(20),
+			},
+		},
+	}
+	b.add(block)
+	b.receipts[hash1] = []*ethgo.Receipt{
+		{
+			Status: 1,
+			Logs: []*ethgo.Log{
+				{Address: addr1},
+				{Address: addr2},
+			},
+		},
+		{
+			Status:          1,
+			ContractAddress: addr0,
+			Logs: []*ethgo.Log{
+				{Address: addr1, Topics: []ethgo.Hash{hash1}},
+			},
+		},
+	}
+
+	eth := NewEth(b)
+
+	receipts, err := eth.GetBlockReceipts(BlockNumberOrHashWithHash(hash1, false))
+	assert.NoError(t, err)
+	assert.Len(t, receipts, 2)
+
+	assert.Equal(t, receipts[0].TransactionHash, ethgo.Hash{0x1})
+	assert.Equal(t, receipts[0].BlockHash, hash1)
+	assert.Equal(t, receipts[0].BlockNumber, argUint64(1))
+	assert.Equal(t, receipts[0].To, &addr2)
+	assert.Nil(t, receipts[0].ContractAddress)
+	assert.Equal(t, (*big.Int)(&receipts[0].EffectiveGasPrice).Uint64(), uint64(150))
+	assert.Len(t, receipts[0].LogsBloom, bloomSize)
+
+	assert.Equal(t, receipts[1].TransactionIndex, argUint64(1))
+	assert.Equal(t, receipts[1].Type, argUint64(ethgo.TransactionDynamicFee))
+	assert.Equal(t, *receipts[1].ContractAddress, addr0)
+	assert.Equal(t, (*big.Int)(&receipts[1].EffectiveGasPrice).Uint64(), uint64(120))
+
+	// the log indexes are relative to the block
+	log := receipts[1].Logs[0]
+	assert.Equal(t, log.LogIndex, uint64(2))
+	assert.Equal(t, log.TransactionIndex, uint64(1))
+	assert.Equal(t, log.TransactionHash, ethgo.Hash{0x2})
+	assert.Equal(t, log.BlockHash, hash1)
+
+	// the backend logs are not modified
+	assert.Equal(t, b.receipts[hash1][1].Logs[0].LogIndex, uint64(0))
+
+	receipts, err = eth.GetBlockReceipts(BlockNumberOrHashWithNumber(LatestBlockNumber))
+	assert.NoError(t, err)
+	assert.Len(t, receipts, 2)
+
+	receipts, err = eth.GetBlockReceipts(BlockNumberOrHashWithNumber(BlockNumber(0)))
+	assert.NoError(t, err)
+	assert.Empty(t, receipts)
+
+	// unknown block
+	receipts, err = eth.GetBlockReceipts(BlockNumberOrHashWithHash(hash2, false))
+	assert.NoError(t, err)
+	assert.Nil(t, receipts)
+}
+
+func TestCreateBloom(t *testing.T) {
+	logs := []*ethgo.Log{
+		{Address: addr1, Topics: []ethgo.Hash{hash1}},
+	}
+	bloom := createBloom(logs)
+
+	// every item sets at most three bits
+	bits := 0
+	for _, b := range bloom {
+		for ; b != 0; b &= b - 1 {
+			bits++
+		}
+	}
+	assert.True(t, bits > 0 && bits <= 6)
+	assert.Equal(t, createBloom(nil), make([]byte, bloomSize))
+}
+
+type mockTxnResultStore struct {
+	mockFeeHistoryStore
+	txns map[ethgo.Hash]*TransactionResult
+}
+
+func (m *mockTxnResultStore) GetTransactionByHash(hash ethgo.Hash) (*TransactionResult, error) {
+	return m.txns[hash], nil
+}
+
+func TestEth_GetTransactionByHash(t *testing.T) {
+	b := &mockTxnResultStore{
+		mockFeeHistoryStore: mockFeeHistoryStore{baseFee: big.NewInt(100)},
+	}
+	b.addBlock(0)
+
+	mined := &ethgo.Transaction{
+		Type:                 ethgo.TransactionDynamicFee,
+		Hash:                 ethgo.Hash{0x2},
+		From:                 addr1,
+		MaxFeePerGas:         big.NewInt(300),
+		MaxPriorityFeePerGas: big.NewInt(20),
+	}
+	block := &ethgo.Block{
+		Number: 1,
+		Hash:   hash1,
+		Transactions: []*ethgo.Transaction{
+			{Hash: ethgo.Hash{0x1}, GasPrice: 150},
+			mined,
+		},
+	}
+	b.add(block)
+	minedReceipt := &ethgo.Receipt{
+		BlockHash:       hash1,
+		Status:          1,
+		ContractAddress: addr0,
+		GasUsed:         30,
+		Logs:            []*ethgo.Log{{Address: addr2}},
+	}
+	b.receipts[hash1] = []*ethgo.Receipt{
+		{GasUsed: 20, Logs: []*ethgo.Log{{Address: addr1}}},
+		minedReceipt,
+	}
+
+	// the receipt is not available but the transaction has the block fields
+	unindexed := &ethgo.Transaction{
+		Hash:        ethgo.Hash{0x3},
+		BlockHash:   hash2,
+		BlockNumber: 10,
+		TxnIndex:    2,
+	}
+	pending := &ethgo.Transaction{
+		Hash: ethgo.Hash{0x4},
+	}
+
+	// the backend only returns the hash of the block in the receipt
+	b.txns = map[ethgo.Hash]*TransactionResult{
+		mined.Hash: {
+			Transaction: &ethgo.Transaction{Type: mined.Type, Hash: mined.Hash, From: mined.From, MaxFeePerGas: mined.MaxFeePerGas, MaxPriorityFeePerGas: mined.MaxPriorityFeePerGas},
+			Receipt:     minedReceipt,
+		},
+		unindexed.Hash: {
+			Transaction: unindexed,
+		},
+		pending.Hash: {
+			Transaction: pending,
+		},
+	}
+
+	eth := NewEth(b)
+
+	txn, err := eth.GetTransactionByHash(mined.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, *txn.BlockHash, hash1)
+	assert.Equal(t, *txn.BlockNumber, argUint64(1))
+	assert.Equal(t, *txn.TransactionIndex, argUint64(1))
+	assert.Equal(t, (*big.Int)(&txn.GasPrice).Uint64(), uint64(120))
+
+	receipt, err := eth.GetTransactionReceipt(mined.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, receipt.BlockHash, hash1)
+	assert.Equal(t, receipt.BlockNumber, argUint64(1))
+	assert.Equal(t, receipt.TransactionIndex, argUint64(1))
+	assert.Equal(t, receipt.Status, argUint64(1))
+	assert.Equal(t, receipt.Type, argUint64(ethgo.TransactionDynamicFee))
+	assert.Equal(t, *receipt.ContractAddress, addr0)
+	assert.Equal(t, (*big.Int)(&receipt.EffectiveGasPrice).Uint64(), uint64(120))
+	assert.Equal(t, receipt.Logs[0].LogIndex, uint64(1))
+	assert.Equal(t, receipt.Logs[0].TransactionHash, mined.Hash)
+
+	// the cumulative gas includes the previous transactions
+	assert.Equal(t, argUint64(50), receipt.CumulativeGasUsed)
+
+	// the block fields of the transaction are used without a receipt
+	txn, err = eth.GetTransactionByHash(unindexed.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, hash2, *txn.BlockHash)
+	assert.Equal(t, argUint64(10), *txn.BlockNumber)
+	assert.Equal(t, argUint64(2), *txn.TransactionIndex)
+
+	// the pending transaction has null block fields and no receipt
+	txn, err = eth.GetTransactionByHash(pending.Hash)
+	assert.NoError(t, err)
+	assert.Nil(t, txn.BlockHash)
+	assert.Nil(t, txn.BlockNumber)
+	assert.Nil(t, txn.TransactionIndex)
+
+	receipt, err = eth.GetTransactionReceipt(pending.Hash)
+	assert.NoError(t, err)
+	assert.Nil(t, receipt)
+
+	// unknown transaction
+	txn, err = eth.GetTransactionByHash(ethgo.Hash{0x5})
+	assert.NoError(t, err)
+	assert.Nil(t, txn)
+}
+
+type mockSyncStore struct {
+	nullBlockchainInterface
+	progress *SyncProgress
+}
+
+func (m *mockSyncStore) SyncStatus() (*SyncProgress, bool) {
+	return m.progress, m.progress != nil
+}
+
+func TestEth_Syncing(t *testing.T) {
+	// the backend does not report the sync status
+	res, err := NewEth(&nullBlockchainInterface{}).Syncing()
+	assert.NoError(t, err)
+	assert.Equal(t, res, false)
+
+	b := &mockSyncStore{}
+	eth := NewEth(b)
+
+	res, err = eth.Syncing()
+	assert.NoError(t, err)
+	assert.Equal(t, res, false)
+
+	b.progress = &SyncProgress{StartingBlock: 1, CurrentBlock: 5, HighestBlock: 10}
+
+	res, err = eth.Syncing()
+	assert.NoError(t, err)
+	assert.Equal(t, res, &rpcSyncProgress{
+		StartingBlock: argUint64(1),
+		CurrentBlock:  argUint64(5),
+		HighestBlock:  argUint64(10),
+	})
+}
+
+func TestEth_NodeInfo(t *testing.T) {
+	d := jsonrpc.NewDispatcher()
+	d.Register("eth", NewEth(&nullBlockchainInterface{}))
+
+	cases := map[string]string{
+		"eth_accounts": `[]`,
+		"eth_coinbase": `"0x0000000000000000000000000000000000000000"`,
+		"eth_mining":   `false`,
+		"eth_hashrate": `"0x0"`,
+		"eth_syncing":  `false`,
+	}
+	for method, expected := range cases {
+		res, err := d.Call(method, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, string(res), expected)
+	}
 }
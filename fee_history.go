@@ -0,0 +1,277 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/umbracle/ethgo"
+)
+
+const (
+	// feeCacheSize is the number of processed blocks kept in the fee cache
+	feeCacheSize = 2048
+
+	// elasticityMultiplier and baseFeeChangeDenominator are the EIP-1559 parameters
+	elasticityMultiplier     = 2
+	baseFeeChangeDenominator = 8
+)
+
+type feeHistoryResult struct {
+	OldestBlock   argUint64   `json:"oldestBlock"`
+	BaseFeePerGas []*argBig   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio  []float64   `json:"gasUsedRatio"`
+	Reward        [][]*argBig `json:"reward,omitempty"`
+}
+
+// FeeHistory returns the base fees, gas used ratios and priority fee
+// percentiles of a range of blocks ending at the newest block
+func (e *Eth) FeeHistory(blockCount argUint64, newest BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("invalid reward percentile: %f", p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return nil, fmt.Errorf("invalid reward percentile: #%d:%f > #%d:%f", i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+
+	if newest == PendingBlockNumber {
+		// the pending block has not been executed yet
+		newest = LatestBlockNumber
+	}
+	last, err := e.getBlockNumber(newest)
+	if err != nil {
+		return nil, err
+	}
+
+	count := uint64(blockCount)
+	if count > e.config.FeeHistoryMaxBlocks {
+		count = e.config.FeeHistoryMaxBlocks
+	}
+	if count > last+1 {
+		count = last + 1
+	}
+	if count == 0 {
+		return &feeHistoryResult{GasUsedRatio: []float64{}}, nil
+	}
+
+	oldest := last + 1 - count
+	res := &feeHistoryResult{
+		OldestBlock:  argUint64(oldest),
+		GasUsedRatio: make([]float64, 0, count),
+	}
+
+	var next *big.Int
+	for num := oldest; num <= last; num++ {
+		fees, err := e.getBlockFees(num)
+		if err != nil {
+			return nil, err
+		}
+		res.BaseFeePerGas = append(res.BaseFeePerGas, argBigPtr(fees.baseFee))
+		res.GasUsedRatio = append(res.GasUsedRatio, fees.gasUsedRatio)
+		if len(rewardPercentiles) != 0 {
+			rewards := []*argBig{}
+			for _, reward := range fees.rewards(rewardPercentiles) {
+				rewards = append(rewards, argBigPtr(reward))
+			}
+			res.Reward = append(res.Reward, rewards)
+		}
+		next = fees.nextBaseFee
+	}
+	// include the base fee of the block after the newest one
+	res.BaseFeePerGas = append(res.BaseFeePerGas, argBigPtr(next))
+
+	return res, nil
+}
+
+// MaxPriorityFeePerGas returns the suggested priority fee for dynamic fee transactions
+func (e *Eth) MaxPriorityFeePerGas() (*argBig, error) {
+	return argBigPtr(e.suggestGasTipCap(e.baseFee(e.b.Header()))), nil
+}
+
+// getBlockFees returns the processed fees of a block
+func (e *Eth) getBlockFees(num uint64) (*blockFees, error) {
+	// resolve the hash with the header so the cached blocks are not loaded in full
+	header, ok := e.b.GetBlockByNumber(num, false)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+	if fees, ok := e.feeCache.get(header.Hash); ok {
+		return fees, nil
+	}
+
+	block, ok := e.b.GetBlockByHash(header.Hash, true)
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", header.Hash)
+	}
+	receipts, err := e.b.GetReceiptsByHash(block.Hash)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := processBlockFees(block, e.baseFee(block), receipts)
+	if err != nil {
+		return nil, err
+	}
+	e.feeCache.add(block.Hash, fees)
+	return fees, nil
+}
+
+// blockFees are the fee values of a block
+type blockFees struct {
+	baseFee      *big.Int
+	nextBaseFee  *big.Int
+	gasUsed      uint64
+	gasUsedRatio float64
+
+	// transactions sorted by the effective priority fee
+	txs []*txFees
+}
+
+type txFees struct {
+	gasUsed uint64
+	reward  *big.Int
+}
+
+func processBlockFees(block *ethgo.Block, baseFee *big.Int, receipts []*ethgo.Receipt) (*blockFees, error) {
+	if len(receipts) != len(block.Transactions) {
+		return nil, fmt.Errorf("block %d has %d transactions but %d receipts", block.Number, len(block.Transactions), len(receipts))
+	}
+
+	fees := &blockFees{
+		baseFee:     bigOrZero(baseFee),
+		nextBaseFee: new(big.Int),
+		gasUsed:     block.GasUsed,
+	}
+	if block.GasLimit != 0 {
+		fees.gasUsedRatio = float64(block.GasUsed) / float64(block.GasLimit)
+	}
+	if baseFee != nil {
+		fees.nextBaseFee = calcNextBaseFee(block, baseFee)
+	}
+
+	for indx, txn := range block.Transactions {
+		fees.txs = append(fees.txs, &txFees{
+			gasUsed: receipts[indx].GasUsed,
+			reward:  effectiveTip(txn, baseFee),
+		})
+	}
+	sort.Slice(fees.txs, func(i, j int) bool {
+		return fees.txs[i].reward.Cmp(fees.txs[j].reward) < 0
+	})
+	return fees, nil
+}
+
+// rewards returns the priority fees paid at each percentile of the gas used in the block
+func (b *blockFees) rewards(percentiles []float64) []*big.Int {
+	res := make([]*big.Int, len(percentiles))
+	if len(b.txs) == 0 {
+		for i := range res {
+			res[i] = new(big.Int)
+		}
+		return res
+	}
+
+	sumGasUsed := b.txs[0].gasUsed
+	indx := 0
+	for i, p := range percentiles {
+		threshold := uint64(float64(b.gasUsed) * p / 100)
+		for sumGasUsed < threshold && indx < len(b.txs)-1 {
+			indx++
+			sumGasUsed += b.txs[indx].gasUsed
+		}
+		res[i] = b.txs[indx].reward
+	}
+	return res
+}
+
+// effectiveTip returns the priority fee paid by the transaction
+func effectiveTip(txn *ethgo.Transaction, baseFee *big.Int) *big.Int {
+	if txn.Type == ethgo.TransactionDynamicFee {
+		tip := bigOrZero(txn.MaxPriorityFeePerGas)
+		if baseFee == nil {
+			return new(big.Int).Set(tip)
+		}
+		maxTip := new(big.Int).Sub(bigOrZero(txn.MaxFeePerGas), baseFee)
+		if maxTip.Cmp(tip) < 0 {
+			tip = maxTip
+		}
+		return new(big.Int).Set(tip)
+	}
+
+	tip := new(big.Int).SetUint64(txn.GasPrice)
+	if baseFee != nil {
+		tip.Sub(tip, baseFee)
+	}
+	return tip
+}
+
+// calcNextBaseFee computes the base fee of the child of the block (EIP-1559)
+func calcNextBaseFee(parent *ethgo.Block, baseFee *big.Int) *big.Int {
+	target := parent.GasLimit / elasticityMultiplier
+	if target == 0 || parent.GasUsed == target {
+		return new(big.Int).Set(baseFee)
+	}
+
+	if parent.GasUsed > target {
+		delta := new(big.Int).SetUint64(parent.GasUsed - target)
+		delta.Mul(delta, baseFee)
+		delta.Div(delta, new(big.Int).SetUint64(target))
+		delta.Div(delta, big.NewInt(baseFeeChangeDenominator))
+		if delta.Sign() == 0 {
+			delta.SetUint64(1)
+		}
+		return delta.Add(delta, baseFee)
+	}
+
+	delta := new(big.Int).SetUint64(target - parent.GasUsed)
+	delta.Mul(delta, baseFee)
+	delta.Div(delta, new(big.Int).SetUint64(target))
+	delta.Div(delta, big.NewInt(baseFeeChangeDenominator))
+
+	next := new(big.Int).Sub(baseFee, delta)
+	if next.Sign() < 0 {
+		next.SetUint64(0)
+	}
+	return next
+}
+
+// feeCache is a fixed size cache of processed blocks that evicts
+// the oldest entry once it is full
+type feeCache struct {
+	lock  sync.Mutex
+	size  int
+	items map[ethgo.Hash]*blockFees
+	keys  []ethgo.Hash
+}
+
+func newFeeCache(size int) *feeCache {
+	return &feeCache{
+		size:  size,
+		items: map[ethgo.Hash]*blockFees{},
+	}
+}
+
+func (f *feeCache) get(hash ethgo.Hash) (*blockFees, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	fees, ok := f.items[hash]
+	return fees, ok
+}
+
+func (f *feeCache) add(hash ethgo.Hash, fees *blockFees) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, ok := f.items[hash]; ok {
+		return
+	}
+	if len(f.keys) == f.size {
+		delete(f.items, f.keys[0])
+		f.keys = f.keys[1:]
+	}
+	f.items[hash] = fees
+	f.keys = append(f.keys, hash)
+}
@@ -0,0 +1,141 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+)
+
+type mockFeeHistoryStore struct {
+	mockBlockStore
+	receipts map[ethgo.Hash][]*ethgo.Receipt
+	baseFee  *big.Int
+	calls    int
+}
+
+// addBlock adds a block with a legacy transaction for each gas price
+func (m *mockFeeHistoryStore) addBlock(gasUsed uint64, gasPrices ...uint64) {
+	if m.receipts == nil {
+		m.receipts = map[ethgo.Hash][]*ethgo.Receipt{}
+	}
+
+	num := uint64(len(m.blocks))
+	block := &ethgo.Block{
+		Number:   num,
+		Hash:     ethgo.Hash{byte(num + 1)},
+		GasLimit: 1000,
+		GasUsed:  gasUsed,
+	}
+	receipts := []*ethgo.Receipt{}
+	for _, price := range gasPrices {
+		block.Transactions = append(block.Transactions, &ethgo.Transaction{
+			GasPrice: price,
+		})
+		receipts = append(receipts, &ethgo.Receipt{
+			GasUsed: gasUsed / uint64(len(gasPrices)),
+		})
+	}
+	m.receipts[block.Hash] = receipts
+	m.add(block)
+}
+
+func (m *mockFeeHistoryStore) GetReceiptsByHash(hash ethgo.Hash) ([]*ethgo.Receipt, error) {
+	m.calls++
+	return m.receipts[hash], nil
+}
+
+func (m *mockFeeHistoryStore) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, bool) {
+	if full {
+		m.calls++
+	}
+	return m.mockBlockStore.GetBlockByHash(hash, full)
+}
+
+func (m *mockFeeHistoryStore) GetBlockByNumber(num uint64, full bool) (*ethgo.Block, bool) {
+	if full {
+		m.calls++
+	}
+	return m.mockBlockStore.GetBlockByNumber(num, full)
+}
+
+func (m *mockFeeHistoryStore) BaseFee(block *ethgo.Block) *big.Int {
+	return m.baseFee
+}
+
+func TestEth_FeeHistory(t *testing.T) {
+	b := &mockFeeHistoryStore{baseFee: big.NewInt(100)}
+	b.addBlock(0)
+	b.addBlock(500, 110, 120)
+	b.addBlock(1000, 130, 140, 150, 160)
+
+	eth := NewEth(b)
+
+	res, err := eth.FeeHistory(argUint64(10), LatestBlockNumber, []float64{0, 50, 100})
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(res.OldestBlock), uint64(0))
+	assert.Equal(t, res.GasUsedRatio, []float64{0, 0.5, 1})
+
+	baseFees := []uint64{}
+	for _, fee := range res.BaseFeePerGas {
+		baseFees = append(baseFees, (*big.Int)(fee).Uint64())
+	}
+	// the last base fee is the one of the next block
+	assert.Equal(t, baseFees, []uint64{100, 100, 100, 112})
+
+	rewards := [][]uint64{}
+	for _, blockRewards := range res.Reward {
+		r := []uint64{}
+		for _, reward := range blockRewards {
+			r = append(r, (*big.Int)(reward).Uint64())
+		}
+		rewards = append(rewards, r)
+	}
+	assert.Equal(t, rewards, [][]uint64{{0, 0, 0}, {10, 10, 20}, {30, 40, 60}})
+
+	// the processed blocks are cached and neither the
+	// full blocks nor the receipts are loaded again
+	calls := b.calls
+	_, err = eth.FeeHistory(argUint64(10), LatestBlockNumber, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, calls, b.calls)
+
+	// wrong percentiles
+	_, err = eth.FeeHistory(argUint64(10), LatestBlockNumber, []float64{50, 10})
+	assert.Error(t, err)
+
+	_, err = eth.FeeHistory(argUint64(10), LatestBlockNumber, []float64{101})
+	assert.Error(t, err)
+}
+
+func TestEth_FeeHistory_MaxBlocks(t *testing.T) {
+	b := &mockFeeHistoryStore{}
+	for i := 0; i < 10; i++ {
+		b.addBlock(500, 10)
+	}
+
+	eth := NewEth(b, WithFeeHistoryMaxBlocks(3))
+
+	res, err := eth.FeeHistory(argUint64(5), BlockNumber(8), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(res.OldestBlock), uint64(6))
+	assert.Len(t, res.GasUsedRatio, 3)
+	assert.Len(t, res.BaseFeePerGas, 4)
+	assert.Nil(t, res.Reward)
+}
+
+func TestEth_MaxPriorityFeePerGas(t *testing.T) {
+	b := &mockFeeHistoryStore{baseFee: big.NewInt(100)}
+	b.addBlock(0)
+	b.addBlock(500, 110)
+	b.addBlock(500, 130)
+	b.addBlock(500, 120)
+
+	eth := NewEth(b)
+
+	tip, err := eth.MaxPriorityFeePerGas()
+	assert.NoError(t, err)
+	assert.Equal(t, (*big.Int)(tip).Uint64(), uint64(20))
+}
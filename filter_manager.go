@@ -131,6 +131,9 @@ type FilterManager struct {
 	filters map[string]*Filter
 	lock    sync.Mutex
 
+	// streams with active filters
+	streams map[jsonrpc.Stream]struct{}
+
 	updateCh chan struct{}
 	timer    timeHeapImpl
 	timeout  time.Duration
@@ -147,6 +150,7 @@ func NewFilterManager(logger *log.Logger, store blockchainInterface) *FilterMana
 		store:       store,
 		closeCh:     make(chan struct{}),
 		filters:     map[string]*Filter{},
+		streams:     map[jsonrpc.Stream]struct{}{},
 		updateCh:    make(chan struct{}),
 		timer:       timeHeapImpl{},
 		blockStream: &blockStream{},
@@ -212,7 +216,7 @@ func (f *FilterManager) Run() {
 
 func (f *FilterManager) nextTimeoutFilter() *Filter {
 	f.lock.Lock()
-	if len(f.filters) == 0 {
+	if len(f.timer) == 0 {
 		f.lock.Unlock()
 		return nil
 	}
@@ -243,7 +247,7 @@ func (f *FilterManager) dispatchEvent(evnt *Event) error {
 			// check the logs with the filters
 			for _, log := range receipt.Logs {
 				for _, f := range f.filters {
-					if f.isLogFilter() {
+					if f.isLogFilter() && f.logFilter.matchBlock(h.Number) {
 						if f.logFilter.Match(log) {
 							f.logs = append(f.logs, log)
 						}
@@ -301,19 +305,58 @@ func (f *FilterManager) GetFilterChanges(id string) (string, error) {
 	return res, nil
 }
 
+// GetLogFilter returns the log filter of an installed filter
+func (f *FilterManager) GetLogFilter(id string) (*LogFilter, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	item, ok := f.filters[id]
+	if !ok {
+		return nil, errFilterDoesNotExists
+	}
+	if !item.isLogFilter() {
+		return nil, fmt.Errorf("filter is not a log filter")
+	}
+	return item.logFilter, nil
+}
+
 func (f *FilterManager) Uninstall(id string) bool {
 	f.lock.Lock()
+	defer f.lock.Unlock()
 
 	item, ok := f.filters[id]
 	if !ok {
 		return false
 	}
+	f.removeFilterLocked(item)
+	return true
+}
+
+func (f *FilterManager) removeFilterLocked(item *Filter) {
+	delete(f.filters, item.id)
+	if !item.isWS() {
+		// websocket filters do not timeout
+		heap.Remove(&f.timer, item.index)
+	}
+}
 
-	delete(f.filters, id)
-	heap.Remove(&f.timer, item.index)
+// watchStream uninstalls all the filters of the stream once it is closed
+func (f *FilterManager) watchStream(stream jsonrpc.Stream) {
+	select {
+	case <-stream.Closed():
+	case <-f.closeCh:
+		return
+	}
 
-	f.lock.Unlock()
-	return true
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, item := range f.filters {
+		if item.stream == stream {
+			f.removeFilterLocked(item)
+		}
+	}
+	delete(f.streams, stream)
 }
 
 func (f *FilterManager) NewBlockFilter(stream jsonrpc.Stream) string {
@@ -342,8 +385,13 @@ func (f *FilterManager) addFilter(logFilter *LogFilter, stream jsonrpc.Stream) s
 	}
 
 	f.filters[filter.id] = filter
-	filter.timestamp = time.Now().Add(f.timeout)
-	heap.Push(&f.timer, filter)
+	if stream == nil {
+		filter.timestamp = time.Now().Add(f.timeout)
+		heap.Push(&f.timer, filter)
+	} else if _, ok := f.streams[stream]; !ok {
+		f.streams[stream] = struct{}{}
+		go f.watchStream(stream)
+	}
 
 	f.lock.Unlock()
 
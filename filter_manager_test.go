@@ -172,8 +172,38 @@ func TestFilter_Websocket(t *testing.T) {
 	}
 }
 
+func TestFilter_WebsocketClose(t *testing.T) {
+	store := newMockStore()
+
+	mock := &mockWsConn{
+		msgCh:   make(chan []byte, 1),
+		closeCh: make(chan struct{}),
+	}
+
+	m := NewFilterManager(nil, store)
+	m.timeout = 1 * time.Second
+
+	go m.Run()
+
+	id0 := m.NewBlockFilter(mock)
+	id1 := m.NewLogFilter(&LogFilter{}, mock)
+
+	// websocket filters do not timeout
+	time.Sleep(2 * time.Second)
+	assert.True(t, m.Exists(id0))
+	assert.True(t, m.Exists(id1))
+
+	// the filters are removed once the connection is closed
+	close(mock.closeCh)
+	time.Sleep(500 * time.Millisecond)
+
+	assert.False(t, m.Exists(id0))
+	assert.False(t, m.Exists(id1))
+}
+
 type mockWsConn struct {
-	msgCh chan []byte
+	msgCh   chan []byte
+	closeCh chan struct{}
 }
 
 func (m *mockWsConn) WriteMessage(b []byte) error {
@@ -181,6 +211,10 @@ func (m *mockWsConn) WriteMessage(b []byte) error {
 	return nil
 }
 
+func (m *mockWsConn) Closed() <-chan struct{} {
+	return m.closeCh
+}
+
 func TestFilter_HeadStream(t *testing.T) {
 	b := &blockStream{}
 
@@ -0,0 +1,94 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/umbracle/ethgo"
+)
+
+// gasPriceSampleTxs is the number of cheapest transactions sampled from each block
+const gasPriceSampleTxs = 3
+
+// gasPriceOracle suggests a priority fee from the effective tips
+// paid by the transactions in the latest blocks
+type gasPriceOracle struct {
+	config    *Config
+	blockFees func(num uint64) (*blockFees, error)
+
+	lock     sync.Mutex
+	lastHead ethgo.Hash
+	lastTip  *big.Int
+}
+
+func newGasPriceOracle(config *Config, blockFees func(num uint64) (*blockFees, error)) *gasPriceOracle {
+	return &gasPriceOracle{
+		config:    config,
+		blockFees: blockFees,
+		lastTip:   new(big.Int),
+	}
+}
+
+// suggestTipCap returns the configured percentile of the tips paid in the
+// last blocks up to the head. The blocks that cannot be sampled are skipped
+// and the last suggestion is used if there are no tips. The result is cached
+// until the head changes.
+func (g *gasPriceOracle) suggestTipCap(head *ethgo.Block) *big.Int {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if head.Hash == g.lastHead && g.lastHead != (ethgo.Hash{}) {
+		return new(big.Int).Set(g.lastTip)
+	}
+
+	ignore := bigOrZero(g.config.GasPriceIgnore)
+
+	// complete is false if any of the blocks cannot be sampled, in
+	// which case the suggestion is not cached for the head
+	complete := true
+
+	tips := []*big.Int{}
+	for i := uint64(0); i < g.config.GasPriceBlocks && i <= head.Number; i++ {
+		fees, err := g.blockFees(head.Number - i)
+		if err != nil {
+			complete = false
+			continue
+		}
+
+		// the transactions are sorted by tip
+		sampled := 0
+		for _, txn := range fees.txs {
+			if sampled == gasPriceSampleTxs {
+				break
+			}
+			if txn.reward.Cmp(ignore) < 0 {
+				continue
+			}
+			tips = append(tips, txn.reward)
+			sampled++
+		}
+	}
+
+	tip := g.lastTip
+	if len(tips) != 0 {
+		sort.Slice(tips, func(i, j int) bool {
+			return tips[i].Cmp(tips[j]) < 0
+		})
+		percentile := g.config.GasPricePercentile
+		if percentile > 100 {
+			percentile = 100
+		}
+		tip = tips[(len(tips)-1)*int(percentile)/100]
+	}
+	if max := g.config.GasPriceMax; max != nil && tip.Cmp(max) > 0 {
+		tip = max
+	}
+
+	if complete {
+		g.lastHead = head.Hash
+	}
+	g.lastTip = new(big.Int).Set(tip)
+
+	return new(big.Int).Set(tip)
+}
@@ -0,0 +1,86 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGasPriceOracle(t *testing.T) {
+	b := &mockFeeHistoryStore{baseFee: big.NewInt(100)}
+	b.addBlock(0)
+	b.addBlock(500, 101, 110, 120, 130, 140)
+	b.addBlock(500, 150, 160)
+
+	eth := NewEth(b, WithGasPriceOracle(10, 50), WithGasPriceLimits(big.NewInt(5), big.NewInt(55)))
+
+	// the tip of 1 is ignored and only the three cheapest
+	// tips of each block are sampled: 10, 20, 30, 50, 60
+	tip := eth.oracle.suggestTipCap(b.Header())
+	assert.Equal(t, tip.Uint64(), uint64(30))
+
+	price, err := eth.GasPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, (*big.Int)(price.(*argBig)).Uint64(), uint64(130))
+
+	// the suggestion is cached for the same head
+	calls := b.calls
+	eth.oracle.suggestTipCap(b.Header())
+	assert.Equal(t, calls, b.calls)
+
+	// the suggestion is capped by the max price
+	b.addBlock(500, 200, 210, 220)
+	b.addBlock(500, 200, 210, 220)
+
+	tip = eth.oracle.suggestTipCap(b.Header())
+	assert.Equal(t, tip.Uint64(), uint64(55))
+}
+
+func TestGasPriceOracle_MissingBlocks(t *testing.T) {
+	b := &mockFeeHistoryStore{baseFee: big.NewInt(100)}
+	b.addBlock(0)
+	b.addBlock(500, 110, 120)
+
+	eth := NewEth(b, WithGasPriceOracle(2, 100))
+	ethLast := NewEth(b, WithGasPriceOracle(1, 100))
+
+	assert.Equal(t, uint64(20), eth.oracle.suggestTipCap(b.Header()).Uint64())
+	assert.Equal(t, uint64(20), ethLast.oracle.suggestTipCap(b.Header()).Uint64())
+
+	// the receipts of the new block are not available
+	b.addBlock(500, 150)
+	delete(b.receipts, b.Header().Hash)
+
+	// the block is skipped
+	assert.Equal(t, uint64(20), eth.oracle.suggestTipCap(b.Header()).Uint64())
+
+	// the last tip is used if no block can be sampled
+	price, err := ethLast.GasPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(120), (*big.Int)(price.(*argBig)).Uint64())
+
+	// the suggestion is not cached for the head
+	calls := b.calls
+	ethLast.oracle.suggestTipCap(b.Header())
+	assert.NotEqual(t, calls, b.calls)
+}
+
+func TestGasPriceOracle_Empty(t *testing.T) {
+	b := &mockFeeHistoryStore{}
+	b.addBlock(0)
+
+	eth := NewEth(b)
+
+	price, err := eth.GasPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, (*big.Int)(price.(*argBig)).Uint64(), uint64(0))
+}
+
+func TestGasPriceOracle_BackendOverride(t *testing.T) {
+	eth := NewEth(&mockFeeStore{})
+
+	price, err := eth.GasPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, (*big.Int)(price.(*argBig)).Uint64(), uint64(150))
+}
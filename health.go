@@ -0,0 +1,35 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReadinessBackend is an optional interface for backends that report
+// additional readiness conditions (i.e. number of peers or sync status)
+type ReadinessBackend interface {
+	// Ready returns an error if the node is not ready to serve requests
+	Ready() error
+}
+
+// NewReadinessCheck returns a readiness check for the jsonrpc server. The node
+// is not ready if the head of the chain is older than maxLag (zero disables
+// the check) or if the backend reports that it is not ready.
+func NewReadinessCheck(b EthBackend, maxLag time.Duration) func() error {
+	return func() error {
+		head := b.Header()
+		if head == nil {
+			return fmt.Errorf("head block not found")
+		}
+		if maxLag != 0 {
+			lag := time.Since(time.Unix(int64(head.Timestamp), 0))
+			if lag > maxLag {
+				return fmt.Errorf("head block %d is %s old, max lag is %s", head.Number, lag.Truncate(time.Second), maxLag)
+			}
+		}
+		if rb, ok := b.(ReadinessBackend); ok {
+			return rb.Ready()
+		}
+		return nil
+	}
+}
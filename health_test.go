@@ -0,0 +1,45 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+)
+
+type mockReadyStore struct {
+	mockBlockStore
+	ready error
+}
+
+func (m *mockReadyStore) Ready() error {
+	return m.ready
+}
+
+func TestReadinessCheck(t *testing.T) {
+	b := &mockReadyStore{}
+	b.add(&ethgo.Block{
+		Number:    1,
+		Timestamp: uint64(time.Now().Unix()),
+	})
+
+	check := NewReadinessCheck(b, time.Minute)
+	assert.NoError(t, check())
+
+	// the backend is not ready
+	b.ready = fmt.Errorf("no peers")
+	assert.Error(t, check())
+
+	// the head is too old
+	b.ready = nil
+	b.add(&ethgo.Block{
+		Number:    2,
+		Timestamp: uint64(time.Now().Add(-2 * time.Minute).Unix()),
+	})
+	assert.Error(t, check())
+
+	// no max lag
+	assert.NoError(t, NewReadinessCheck(b, 0)())
+}
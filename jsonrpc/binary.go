@@ -0,0 +1,465 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+)
+
+// The binary transport exchanges length prefixed frames over a tcp connection
+// and avoids the jsonrpc envelope of the requests. Each frame is encoded as:
+//
+//	| length (uint32) | kind (uint8) | id (uint64) | payload |
+//
+// A call frame payload is the method name prefixed by its length (uint16)
+// followed by the json encoded params. A result frame payload is the json
+// encoded result and an error frame payload is the json encoded ErrorObject.
+// Notification frames (i.e. subscriptions) have a zero id and the same
+// payload as the jsonrpc notification.
+const (
+	frameCall byte = iota
+	frameResult
+	frameError
+	frameNotification
+)
+
+const (
+	frameHeaderSize = 1 + 8
+
+	// maxFrameSize is the maximum size of a frame
+	maxFrameSize = 32 * 1024 * 1024
+
+	// defaultBinaryMaxConcurrentCalls is the default number of calls of
+	// a binary connection that are processed concurrently
+	defaultBinaryMaxConcurrentCalls = 64
+)
+
+type binaryFrame struct {
+	kind    byte
+	id      uint64
+	payload []byte
+}
+
+func writeFrame(w io.Writer, kind byte, id uint64, payload ...[]byte) error {
+	size := frameHeaderSize
+	for _, p := range payload {
+		size += len(p)
+	}
+
+	buf := make([]byte, 4+frameHeaderSize, 4+size)
+	binary.BigEndian.PutUint32(buf[0:4], uint32(size))
+	buf[4] = kind
+	binary.BigEndian.PutUint64(buf[5:13], id)
+	for _, p := range payload {
+		buf = append(buf, p...)
+	}
+
+	_, err := w.Write(buf)
+	return err
+}
+
+func readFrame(r io.Reader) (*binaryFrame, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n < frameHeaderSize {
+		return nil, fmt.Errorf("frame too short: %d", n)
+	}
+	if n > maxFrameSize {
+		return nil, fmt.Errorf("frame too large: %d", n)
+	}
+
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	frame := &binaryFrame{
+		kind:    buf[0],
+		id:      binary.BigEndian.Uint64(buf[1:frameHeaderSize]),
+		payload: buf[frameHeaderSize:],
+	}
+	return frame, nil
+}
+
+func encodeCall(method string, params []byte) []byte {
+	buf := make([]byte, 2, 2+len(method)+len(params))
+	binary.BigEndian.PutUint16(buf, uint16(len(method)))
+	buf = append(buf, method...)
+	buf = append(buf, params...)
+	return buf
+}
+
+func decodeCall(payload []byte) (string, []byte, error) {
+	if len(payload) < 2 {
+		return "", nil, fmt.Errorf("call frame too short")
+	}
+	size := int(binary.BigEndian.Uint16(payload))
+	if len(payload) < 2+size {
+		return "", nil, fmt.Errorf("call frame too short")
+	}
+	return string(payload[2 : 2+size]), payload[2+size:], nil
+}
+
+func (j *Server) setupBinary() error {
+	lis, err := net.Listen("tcp", j.config.BinaryAddr)
+	if err != nil {
+		return err
+	}
+
+	j.binaryAddr = lis.Addr()
+	j.config.Logger.Printf("[INFO] binary server started: addr=%s", j.binaryAddr.String())
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				j.config.Logger.Printf("[ERROR] closed binary connection: %v", err)
+				return
+			}
+			go j.handleBinary(conn)
+		}
+	}()
+	return nil
+}
+
+func (j *Server) handleBinary(c net.Conn) {
+	conn := newBinaryConn(c)
+	defer conn.close()
+
+	// sem limits the number of calls of the connection in flight
+	maxCalls := j.config.BinaryMaxConcurrentCalls
+	if maxCalls <= 0 {
+		maxCalls = defaultBinaryMaxConcurrentCalls
+	}
+	sem := make(chan struct{}, maxCalls)
+
+	for {
+		frame, err := readFrame(conn.reader)
+		if err != nil {
+			return
+		}
+		if frame.kind != frameCall {
+			j.config.Logger.Printf("[DEBUG] unexpected binary frame: kind=%d", frame.kind)
+			return
+		}
+
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+			}()
+			j.handleBinaryCall(conn, frame)
+		}()
+	}
+}
+
+func (j *Server) handleBinaryCall(conn *binaryConn, frame *binaryFrame) {
+	method, params, err := decodeCall(frame.payload)
+	if err != nil {
+		conn.writeError(frame.id, invalidJSONRequest)
+		return
+	}
+	if len(params) == 0 {
+		params = nil
+	}
+
+	result, err := j.dispatcher.Call(method, params, conn)
+	if err != nil {
+		conn.writeError(frame.id, err)
+		return
+	}
+	conn.writeFrame(frameResult, frame.id, result)
+}
+
+// binaryConn is a binary transport connection that implements the Stream interface
+type binaryConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeLock sync.Mutex
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newBinaryConn(conn net.Conn) *binaryConn {
+	return &binaryConn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		closeCh: make(chan struct{}),
+	}
+}
+
+func (b *binaryConn) writeFrame(kind byte, id uint64, payload []byte) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	return writeFrame(b.conn, kind, id, payload)
+}
+
+func (b *binaryConn) writeError(id uint64, err error) error {
+	obj, ok := err.(*ErrorObject)
+	if !ok {
+		obj = &ErrorObject{Code: internalError.Code, Message: err.Error()}
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return b.writeFrame(frameError, id, data)
+}
+
+func (b *binaryConn) WriteMessage(msg []byte) error {
+	return b.writeFrame(frameNotification, 0, msg)
+}
+
+func (b *binaryConn) Closed() <-chan struct{} {
+	return b.closeCh
+}
+
+func (b *binaryConn) close() {
+	b.closeOnce.Do(func() {
+		close(b.closeCh)
+		b.conn.Close()
+	})
+}
+
+// BinaryClient is a client for the binary transport
+type BinaryClient struct {
+	conn *binaryConn
+
+	lock    sync.Mutex
+	seq     uint64
+	pending map[uint64]chan *binaryFrame
+	subs    map[string]*binarySubscription
+	err     error
+
+	// subscribing is the number of subscription calls in flight. While
+	// there are any, the notifications of unknown subscriptions are kept
+	// in early since they may arrive before the subscription id.
+	subscribing int
+	early       map[string][]json.RawMessage
+}
+
+// binarySubscription is a subscription of the binary client
+type binarySubscription struct {
+	namespace string
+	ch        chan json.RawMessage
+}
+
+// subscriptionBufferSize is the number of notifications buffered for a
+// subscription. If the consumer falls behind, the subscription is dropped.
+const subscriptionBufferSize = 128
+
+// DialBinary connects to a binary transport server
+func DialBinary(addr string) (*BinaryClient, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &BinaryClient{
+		conn:    newBinaryConn(conn),
+		pending: map[uint64]chan *binaryFrame{},
+		subs:    map[string]*binarySubscription{},
+		early:   map[string][]json.RawMessage{},
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+func (c *BinaryClient) readLoop() {
+	defer c.conn.close()
+
+	for {
+		frame, err := readFrame(c.conn.reader)
+		if err != nil {
+			c.lock.Lock()
+			c.err = err
+			for id, ch := range c.pending {
+				close(ch)
+				delete(c.pending, id)
+			}
+			for id, sub := range c.subs {
+				close(sub.ch)
+				delete(c.subs, id)
+			}
+			c.lock.Unlock()
+			return
+		}
+
+		if frame.kind == frameNotification {
+			c.handleNotification(frame.payload)
+			continue
+		}
+
+		c.lock.Lock()
+		ch, ok := c.pending[frame.id]
+		delete(c.pending, frame.id)
+		c.lock.Unlock()
+
+		if ok {
+			ch <- frame
+		}
+	}
+}
+
+func (c *BinaryClient) handleNotification(payload []byte) {
+	var msg struct {
+		Params struct {
+			Subscription string          `json:"subscription"`
+			Result       json.RawMessage `json:"result"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return
+	}
+	id := msg.Params.Subscription
+
+	// the notification is sent while holding the lock so that the
+	// channel is not closed concurrently by Unsubscribe
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	sub, ok := c.subs[id]
+	if !ok {
+		if c.subscribing > 0 && len(c.early[id]) < subscriptionBufferSize {
+			c.early[id] = append(c.early[id], msg.Params.Result)
+		}
+		return
+	}
+	c.notify(id, sub, msg.Params.Result)
+}
+
+// notify sends the notification to the subscription. It must be called with the lock held.
+func (c *BinaryClient) notify(id string, sub *binarySubscription, result json.RawMessage) {
+	select {
+	case sub.ch <- result:
+	default:
+		// the consumer is not keeping up, drop the subscription instead
+		// of blocking the responses of the other calls
+		close(sub.ch)
+		delete(c.subs, id)
+		go c.unsubscribe(sub.namespace, id)
+	}
+}
+
+// Call calls the method with the json encoded params
+func (c *BinaryClient) Call(method string, params json.RawMessage) (json.RawMessage, error) {
+	ch := make(chan *binaryFrame, 1)
+
+	c.lock.Lock()
+	if c.err != nil {
+		c.lock.Unlock()
+		return nil, c.err
+	}
+	c.seq++
+	id := c.seq
+	c.pending[id] = ch
+	c.lock.Unlock()
+
+	if err := c.conn.writeFrame(frameCall, id, encodeCall(method, params)); err != nil {
+		c.lock.Lock()
+		delete(c.pending, id)
+		c.lock.Unlock()
+		return nil, err
+	}
+
+	frame, ok := <-ch
+	if !ok {
+		return nil, fmt.Errorf("connection closed")
+	}
+	if frame.kind == frameError {
+		obj := &ErrorObject{}
+		if err := json.Unmarshal(frame.payload, obj); err != nil {
+			return nil, err
+		}
+		return nil, obj
+	}
+	return frame.payload, nil
+}
+
+// Subscribe calls the subscription method (i.e. eth_subscribe) and returns
+// the id of the subscription and a channel with the notifications. The channel
+// is closed if the subscription is removed or the consumer falls behind.
+func (c *BinaryClient) Subscribe(method string, params json.RawMessage) (string, <-chan json.RawMessage, error) {
+	c.lock.Lock()
+	c.subscribing++
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		c.subscribing--
+		if c.subscribing == 0 {
+			c.early = map[string][]json.RawMessage{}
+		}
+		c.lock.Unlock()
+	}()
+
+	res, err := c.Call(method, params)
+	if err != nil {
+		return "", nil, err
+	}
+	var id string
+	if err := json.Unmarshal(res, &id); err != nil {
+		return "", nil, err
+	}
+
+	sub := &binarySubscription{
+		namespace: strings.SplitN(method, "_", 2)[0],
+		ch:        make(chan json.RawMessage, subscriptionBufferSize),
+	}
+
+	c.lock.Lock()
+	if c.err != nil {
+		c.lock.Unlock()
+		return "", nil, c.err
+	}
+	c.subs[id] = sub
+
+	// deliver the notifications received before the subscription id
+	for _, result := range c.early[id] {
+		c.notify(id, sub, result)
+	}
+	delete(c.early, id)
+	c.lock.Unlock()
+
+	return id, sub.ch, nil
+}
+
+// Unsubscribe removes the subscription and closes its notifications channel
+func (c *BinaryClient) Unsubscribe(id string) error {
+	c.lock.Lock()
+	sub, ok := c.subs[id]
+	if ok {
+		close(sub.ch)
+		delete(c.subs, id)
+	}
+	c.lock.Unlock()
+
+	if !ok {
+		return fmt.Errorf("subscription %s not found", id)
+	}
+	return c.unsubscribe(sub.namespace, id)
+}
+
+func (c *BinaryClient) unsubscribe(namespace, id string) error {
+	params, err := json.Marshal([]string{id})
+	if err != nil {
+		return err
+	}
+	_, err = c.Call(namespace+"_unsubscribe", params)
+	return err
+}
+
+// Close closes the connection
+func (c *BinaryClient) Close() error {
+	c.conn.close()
+	return nil
+}
@@ -0,0 +1,207 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBinaryServer(t *testing.T, opts ...ConfigOption) (*Server, *BinaryClient) {
+	config := DefaultConfig()
+	WithBinaryAddr("127.0.0.1:0")(config)
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	srv := &Server{
+		config:     config,
+		dispatcher: NewDispatcher(),
+	}
+
+	require.NoError(t, srv.setupBinary())
+
+	client, err := DialBinary(srv.binaryAddr.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return srv, client
+}
+
+func TestBinary_Call(t *testing.T) {
+	srv, client := newTestBinaryServer(t)
+	srv.Register("mock", &mockSleepService{})
+
+	res, err := client.Call("mock_sleep", json.RawMessage(`[10]`))
+	require.NoError(t, err)
+	require.Equal(t, "10", string(res))
+
+	_, err = client.Call("mock_unknown", nil)
+	require.Error(t, err)
+
+	obj, ok := err.(*ErrorObject)
+	require.True(t, ok)
+	require.Equal(t, -32601, obj.Code)
+}
+
+func TestBinary_Subscribe(t *testing.T) {
+	srv, client := newTestBinaryServer(t)
+
+	svc := &mockStreamService{streamCh: make(chan Stream, 1)}
+	srv.Register("mock", svc)
+
+	id, notifCh, err := client.Subscribe("mock_subscribe", nil)
+	require.NoError(t, err)
+	require.Equal(t, "a", id)
+
+	stream := <-svc.streamCh
+	require.NoError(t, stream.WriteMessage([]byte(fmt.Sprintf(`{"method": "mock_subscription", "params": {"subscription": "%s", "result": 1}}`, id))))
+
+	select {
+	case res := <-notifCh:
+		require.Equal(t, "1", string(res))
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification not received")
+	}
+
+	// the stream is closed with the connection
+	client.Close()
+
+	select {
+	case <-stream.Closed():
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed")
+	}
+}
+
+type mockEarlyStreamService struct{}
+
+func (m *mockEarlyStreamService) Subscribe(stream Stream) (string, error) {
+	// the notification is written before the subscription id
+	if err := stream.WriteMessage([]byte(`{"method": "mock_subscription", "params": {"subscription": "a", "result": 1}}`)); err != nil {
+		return "", err
+	}
+	return "a", nil
+}
+
+func (m *mockEarlyStreamService) Empty() (interface{}, error) {
+	return nil, nil
+}
+
+func TestBinary_SubscribeEarlyNotification(t *testing.T) {
+	srv, client := newTestBinaryServer(t)
+	srv.Register("mock", &mockEarlyStreamService{})
+
+	_, notifCh, err := client.Subscribe("mock_subscribe", nil)
+	require.NoError(t, err)
+
+	select {
+	case res := <-notifCh:
+		require.Equal(t, "1", string(res))
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification not received")
+	}
+}
+
+func TestBinary_NilResult(t *testing.T) {
+	srv, client := newTestBinaryServer(t)
+	srv.Register("mock", &mockEarlyStreamService{})
+
+	res, err := client.Call("mock_empty", nil)
+	require.NoError(t, err)
+	require.Equal(t, "null", string(res))
+}
+
+func TestBinary_ZeroMaxConcurrentCalls(t *testing.T) {
+	srv, client := newTestBinaryServer(t, func(c *Config) {
+		c.BinaryMaxConcurrentCalls = 0
+	})
+	srv.Register("mock", &mockSleepService{})
+
+	_, err := client.Call("mock_sleep", json.RawMessage(`[0]`))
+	require.NoError(t, err)
+}
+
+func TestBinary_MaxConcurrentCalls(t *testing.T) {
+	srv, client := newTestBinaryServer(t, WithBinaryMaxConcurrentCalls(1))
+	srv.Register("mock", &mockSleepService{})
+
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Call("mock_sleep", json.RawMessage(`[100]`))
+			errCh <- err
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		require.NoError(t, err)
+	}
+
+	// the calls are processed one after the other
+	require.GreaterOrEqual(t, int64(time.Since(now)), int64(200*time.Millisecond))
+}
+
+func TestBinary_Unsubscribe(t *testing.T) {
+	srv, client := newTestBinaryServer(t)
+
+	svc := &mockStreamService{streamCh: make(chan Stream, 1)}
+	srv.Register("mock", svc)
+
+	id, notifCh, err := client.Subscribe("mock_subscribe", nil)
+	require.NoError(t, err)
+	<-svc.streamCh
+
+	require.NoError(t, client.Unsubscribe(id))
+
+	_, ok := <-notifCh
+	require.False(t, ok)
+
+	require.Error(t, client.Unsubscribe(id))
+}
+
+func TestBinary_SubscriptionOverflow(t *testing.T) {
+	srv, client := newTestBinaryServer(t)
+
+	svc := &mockStreamService{streamCh: make(chan Stream, 1)}
+	srv.Register("mock", svc)
+	srv.Register("sleep", &mockSleepService{})
+
+	id, notifCh, err := client.Subscribe("mock_subscribe", nil)
+	require.NoError(t, err)
+
+	// send more notifications than the client buffers without consuming them
+	stream := <-svc.streamCh
+	for i := 0; i < subscriptionBufferSize+1; i++ {
+		require.NoError(t, stream.WriteMessage([]byte(fmt.Sprintf(`{"method": "mock_subscription", "params": {"subscription": "%s", "result": %d}}`, id, i))))
+	}
+
+	// the response is read after the notifications
+	_, err = client.Call("sleep_sleep", json.RawMessage(`[0]`))
+	require.NoError(t, err)
+
+	// the buffered notifications are delivered and the channel is closed
+	num := 0
+	for {
+		select {
+		case _, ok := <-notifCh:
+			if !ok {
+				require.Equal(t, subscriptionBufferSize, num)
+				return
+			}
+			num++
+		case <-time.After(2 * time.Second):
+			t.Fatal("subscription not dropped")
+		}
+	}
+}
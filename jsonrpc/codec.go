@@ -35,3 +35,37 @@ func (e *ErrorObject) Error() string {
 	}
 	return string(data)
 }
+
+// Error is an error that sets the code of the jsonrpc error
+type Error interface {
+	error
+	ErrorCode() int
+}
+
+// DataError is an error that sets the data of the jsonrpc error
+type DataError interface {
+	error
+	ErrorData() interface{}
+}
+
+// defaultErrorCode is the code of the errors returned by the services
+// that do not set one
+const defaultErrorCode = -32000
+
+// toErrorObject converts an error returned by a service into a jsonrpc error
+func toErrorObject(err error) *ErrorObject {
+	if obj, ok := err.(*ErrorObject); ok {
+		return obj
+	}
+	obj := &ErrorObject{
+		Code:    defaultErrorCode,
+		Message: err.Error(),
+	}
+	if e, ok := err.(Error); ok {
+		obj.Code = e.ErrorCode()
+	}
+	if e, ok := err.(DataError); ok {
+		obj.Data = e.ErrorData()
+	}
+	return obj
+}
@@ -3,13 +3,41 @@ package jsonrpc
 import (
 	"io/ioutil"
 	"log"
+	"time"
 )
 
 type Config struct {
 	Addr         string
 	Logger       *log.Logger
 	IpcPath      string
+	BinaryAddr   string
 	MaxBatchSize uint64
+
+	// http server settings
+	HTTPReadTimeout    time.Duration
+	HTTPWriteTimeout   time.Duration
+	HTTPIdleTimeout    time.Duration
+	HTTPMaxHeaderBytes int
+	EnableH2C          bool
+
+	// ReadinessCheck reports whether the node is ready to serve requests
+	ReadinessCheck func() error
+
+	// websocket connection settings
+	WsPingInterval   time.Duration
+	WsPongTimeout    time.Duration
+	WsWriteTimeout   time.Duration
+	WsMaxMessageSize int64
+	WsMaxConns       int
+	WsMaxConnsPerIP  int
+
+	// request ordering for persistent connections (websocket and ipc)
+	OrderedRequests      bool
+	MaxPipelinedRequests int
+
+	// BinaryMaxConcurrentCalls is the number of calls of a binary
+	// connection that are processed concurrently. Zero uses the default.
+	BinaryMaxConcurrentCalls int
 }
 
 type ConfigOption func(*Config)
@@ -26,6 +54,25 @@ func WithIPC(ipcPath string) ConfigOption {
 	}
 }
 
+// WithBinaryAddr enables the binary transport on the given tcp address
+func WithBinaryAddr(addr string) ConfigOption {
+	return func(h *Config) {
+		h.BinaryAddr = addr
+	}
+}
+
+// WithBinaryMaxConcurrentCalls limits the number of calls of a binary
+// connection that are processed concurrently. Once the limit is reached,
+// the connection is not read until one of the calls finishes.
+func WithBinaryMaxConcurrentCalls(max int) ConfigOption {
+	return func(h *Config) {
+		if max < 1 {
+			max = 1
+		}
+		h.BinaryMaxConcurrentCalls = max
+	}
+}
+
 func WithLogger(logger *log.Logger) ConfigOption {
 	return func(h *Config) {
 		h.Logger = logger
@@ -38,8 +85,92 @@ func WithMaxBatchSize(maxBatchSize uint64) ConfigOption {
 	}
 }
 
+// WithHTTPTimeouts sets the read, write and idle timeouts of the http server.
+// A zero value means no timeout. By default only the idle timeout is set.
+func WithHTTPTimeouts(read, write, idle time.Duration) ConfigOption {
+	return func(h *Config) {
+		h.HTTPReadTimeout = read
+		h.HTTPWriteTimeout = write
+		h.HTTPIdleTimeout = idle
+	}
+}
+
+// WithHTTPMaxHeaderBytes sets the maximum size of the http request headers
+func WithHTTPMaxHeaderBytes(size int) ConfigOption {
+	return func(h *Config) {
+		h.HTTPMaxHeaderBytes = size
+	}
+}
+
+// WithH2C enables http/2 without tls (h2c) in the http server
+func WithH2C() ConfigOption {
+	return func(h *Config) {
+		h.EnableH2C = true
+	}
+}
+
+// WithReadinessCheck sets the check used by the /ready endpoint
+func WithReadinessCheck(check func() error) ConfigOption {
+	return func(h *Config) {
+		h.ReadinessCheck = check
+	}
+}
+
+// WithWsKeepAlive sets how often a ping is sent to the websocket clients
+// and how long to wait for the pong before closing the connection
+func WithWsKeepAlive(pingInterval, pongTimeout time.Duration) ConfigOption {
+	return func(h *Config) {
+		h.WsPingInterval = pingInterval
+		h.WsPongTimeout = pongTimeout
+	}
+}
+
+// WithWsWriteTimeout sets the deadline to write a message in a websocket connection
+func WithWsWriteTimeout(timeout time.Duration) ConfigOption {
+	return func(h *Config) {
+		h.WsWriteTimeout = timeout
+	}
+}
+
+// WithWsMaxMessageSize sets the maximum size in bytes of a websocket message
+func WithWsMaxMessageSize(size int64) ConfigOption {
+	return func(h *Config) {
+		h.WsMaxMessageSize = size
+	}
+}
+
+// WithWsMaxConns limits the number of concurrent websocket connections
+// in total and per remote ip. A zero value means no limit.
+func WithWsMaxConns(total, perIP int) ConfigOption {
+	return func(h *Config) {
+		h.WsMaxConns = total
+		h.WsMaxConnsPerIP = perIP
+	}
+}
+
+// WithOrderedRequests makes the websocket and ipc connections reply to the
+// requests in the same order they arrive. Up to pipeline requests of the same
+// connection are processed concurrently, a value of 1 processes the requests
+// of the connection sequentially.
+func WithOrderedRequests(pipeline int) ConfigOption {
+	return func(h *Config) {
+		if pipeline < 1 {
+			pipeline = 1
+		}
+		h.OrderedRequests = true
+		h.MaxPipelinedRequests = pipeline
+	}
+}
+
 func DefaultConfig() *Config {
 	return &Config{
-		Logger: log.New(ioutil.Discard, "", 0),
+		Logger:           log.New(ioutil.Discard, "", 0),
+		HTTPIdleTimeout:  120 * time.Second,
+		WsPingInterval:   30 * time.Second,
+		WsPongTimeout:    30 * time.Second,
+		WsWriteTimeout:   10 * time.Second,
+		WsMaxMessageSize: 32 * 1024 * 1024,
+
+		BinaryMaxConcurrentCalls: defaultBinaryMaxConcurrentCalls,
 	}
 }
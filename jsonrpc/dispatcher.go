@@ -23,16 +23,21 @@ func invalidArguments(method string) error {
 	return &ErrorObject{Code: -32602, Message: fmt.Sprintf("invalid arguments to %s", method)}
 }
 
+func notificationsNotSupported(method string) error {
+	return &ErrorObject{Code: -32601, Message: fmt.Sprintf("notifications not supported for %s", method)}
+}
+
 type serviceData struct {
 	sv      reflect.Value
 	funcMap map[string]*funcData
 }
 
 type funcData struct {
-	inNum int
-	reqt  []reflect.Type
-	fv    reflect.Value
-	isDyn bool
+	inNum     int
+	reqt      []reflect.Type
+	fv        reflect.Value
+	isDyn     bool
+	hasStream bool
 }
 
 func (f *funcData) numParams() int {
@@ -72,11 +77,26 @@ func (d *Dispatcher) getFnHandler(req Request) (*serviceData, *funcData, error)
 	return service, fd, nil
 }
 
+// Stream is a persistent connection (i.e. websocket) that can receive
+// notifications from the server. Methods that take a Stream as their
+// first argument can only be called over a stream.
 type Stream interface {
+	// WriteMessage writes a message to the connection
 	WriteMessage(b []byte) error
+
+	// Closed returns a channel that is closed once the connection is closed
+	Closed() <-chan struct{}
 }
 
+var streamt = reflect.TypeOf((*Stream)(nil)).Elem()
+
+// Handle handles a request without an associated stream
 func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
+	return d.HandleStream(reqBody, nil)
+}
+
+// HandleStream handles a request that arrives from a stream
+func (d *Dispatcher) HandleStream(reqBody []byte, stream Stream) ([]byte, error) {
 	if len(reqBody) == 0 {
 		return nil, fmt.Errorf("empty request")
 	}
@@ -92,7 +112,7 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	} else {
 		// single request
 		var req Request
-		if err := json.Unmarshal(reqBody, &reqs); err != nil {
+		if err := json.Unmarshal(reqBody, &req); err != nil {
 			return nil, invalidJSONRequest
 		}
 		reqs = append(reqs, req)
@@ -104,7 +124,7 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	}
 
 	for _, req := range reqs {
-		resp, err := d.handleReq(req)
+		resp, err := d.handleReq(req, stream)
 		if err != nil {
 			return nil, err
 		}
@@ -122,7 +142,21 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	return out, nil
 }
 
-func (d *Dispatcher) handleReq(req Request) (*Response, error) {
+// Call calls a single method with the json encoded params and returns the
+// json encoded result. It is used by transports without a jsonrpc envelope.
+func (d *Dispatcher) Call(method string, params []byte, stream Stream) ([]byte, error) {
+	resp, err := d.handleReq(Request{Method: method, Params: params}, stream)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Result) == 0 {
+		// a nil result is encoded as null
+		return []byte("null"), nil
+	}
+	return resp.Result, nil
+}
+
+func (d *Dispatcher) handleReq(req Request, stream Stream) (*Response, error) {
 	d.logger.Printf("[DEBUG] request: method=%s, id=%s", req.Method, req.ID)
 
 	if req.Params == nil {
@@ -139,13 +173,22 @@ func (d *Dispatcher) handleReq(req Request) (*Response, error) {
 	// add service
 	inArgs[0] = service.sv
 
+	offset := 1
+	if fd.hasStream {
+		if stream == nil {
+			return nil, notificationsNotSupported(req.Method)
+		}
+		inArgs[1] = reflect.ValueOf(stream)
+		offset = 2
+	}
+
 	// decode function input params from request
-	typs := fd.reqt[1:]
+	typs := fd.reqt[offset:]
 	inputs := make([]interface{}, len(typs))
 	for i := 0; i < len(typs); i++ {
 		val := reflect.New(typs[i])
 		inputs[i] = val.Interface()
-		inArgs[i+1] = val.Elem()
+		inArgs[i+offset] = val.Elem()
 	}
 
 	if err := json.Unmarshal(req.Params, &inputs); err != nil {
@@ -155,7 +198,8 @@ func (d *Dispatcher) handleReq(req Request) (*Response, error) {
 	output := fd.fv.Call(inArgs)
 	err = getError(output[1])
 	if err != nil {
-		return nil, d.internalError(req.Method, err)
+		d.logger.Printf("[DEBUG] request failed: method=%s, err=%v", req.Method, err)
+		return nil, toErrorObject(err)
 	}
 
 	var data []byte
@@ -217,6 +261,11 @@ func (d *Dispatcher) Register(serviceName string, service interface{}) {
 			fd.isDyn = true
 		}
 
+		// check if the first item is the stream of the connection
+		if fd.numParams() > 0 && fd.reqt[1] == streamt {
+			fd.hasStream = true
+		}
+
 		funcMap[name] = fd
 	}
 
@@ -35,7 +35,7 @@ func TestDispatcher_Register(t *testing.T) {
 	for _, c := range cases {
 		resp, err := d.handleReq(Request{
 			Method: c.method,
-		})
+		}, nil)
 		if c.err {
 			require.Error(t, err)
 		} else {
@@ -45,6 +45,50 @@ func TestDispatcher_Register(t *testing.T) {
 	}
 }
 
+func TestDispatcher_Error(t *testing.T) {
+	d := NewDispatcher()
+	d.Register("mock", &mockService{})
+
+	cases := []struct {
+		method string
+		err    *ErrorObject
+	}{
+		{
+			method: "mock_err",
+			err:    &ErrorObject{Code: -32000, Message: "err"},
+		},
+		{
+			method: "mock_dataErr",
+			err:    &ErrorObject{Code: 3, Message: "reverted", Data: "0x01"},
+		},
+		{
+			method: "mock_objErr",
+			err:    &ErrorObject{Code: -32001, Message: "obj"},
+		},
+	}
+
+	for _, c := range cases {
+		_, err := d.handleReq(Request{
+			Method: c.method,
+		}, nil)
+		require.Equal(t, err, c.err)
+	}
+}
+
+type mockDataError struct{}
+
+func (m *mockDataError) Error() string {
+	return "reverted"
+}
+
+func (m *mockDataError) ErrorCode() int {
+	return 3
+}
+
+func (m *mockDataError) ErrorData() interface{} {
+	return "0x01"
+}
+
 type mockService struct {
 }
 
@@ -59,3 +103,11 @@ func (m *mockService) Num() (uint64, error) {
 func (m *mockService) Err() (interface{}, error) {
 	return nil, fmt.Errorf("err")
 }
+
+func (m *mockService) DataErr() (interface{}, error) {
+	return nil, &mockDataError{}
+}
+
+func (m *mockService) ObjErr() (interface{}, error) {
+	return nil, &ErrorObject{Code: -32001, Message: "obj"}
+}
@@ -0,0 +1,92 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"sync"
+)
+
+func (j *Server) setupIPC() error {
+	// remove the socket of a previous run
+	if err := os.Remove(j.config.IpcPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	lis, err := net.Listen("unix", j.config.IpcPath)
+	if err != nil {
+		return err
+	}
+
+	j.config.Logger.Printf("[INFO] ipc server started: path=%s", j.config.IpcPath)
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				j.config.Logger.Printf("[ERROR] closed ipc connection: %v", err)
+				return
+			}
+			go j.handleIPC(conn)
+		}
+	}()
+	return nil
+}
+
+func (j *Server) handleIPC(c net.Conn) {
+	conn := newIPCConn(c)
+	defer conn.close()
+
+	j.serveStream(conn)
+}
+
+// ipcConn is an ipc connection that implements the Stream interface.
+// The messages are sent as a stream of json values.
+type ipcConn struct {
+	conn net.Conn
+	dec  *json.Decoder
+
+	writeLock sync.Mutex
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newIPCConn(conn net.Conn) *ipcConn {
+	return &ipcConn{
+		conn:    conn,
+		dec:     json.NewDecoder(conn),
+		closeCh: make(chan struct{}),
+	}
+}
+
+func (i *ipcConn) ReadMessage() ([]byte, error) {
+	var msg json.RawMessage
+	if err := i.dec.Decode(&msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (i *ipcConn) WriteMessage(b []byte) error {
+	i.writeLock.Lock()
+	defer i.writeLock.Unlock()
+
+	msg := make([]byte, 0, len(b)+1)
+	msg = append(msg, b...)
+	msg = append(msg, '\n')
+
+	_, err := i.conn.Write(msg)
+	return err
+}
+
+func (i *ipcConn) Closed() <-chan struct{} {
+	return i.closeCh
+}
+
+func (i *ipcConn) close() {
+	i.closeOnce.Do(func() {
+		close(i.closeCh)
+		i.conn.Close()
+	})
+}
@@ -1,11 +1,16 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 var upgrader = websocket.Upgrader{}
@@ -34,10 +39,21 @@ func (s serverType) String() string {
 type Server struct {
 	config     *Config
 	dispatcher dispatcherImpl
+
+	// address of the binary transport listener
+	binaryAddr net.Addr
+
+	// number of open websocket connections, in total and per ip
+	connsLock  sync.Mutex
+	numConns   int
+	connsPerIP map[string]int
 }
 
 type dispatcherImpl interface {
 	Handle(reqBody []byte) ([]byte, error)
+	HandleStream(reqBody []byte, stream Stream) ([]byte, error)
+	Call(method string, params []byte, stream Stream) ([]byte, error)
+	Register(serviceName string, service interface{})
 }
 
 func NewServer(opts ...ConfigOption) (*Server, error) {
@@ -55,9 +71,26 @@ func NewServer(opts ...ConfigOption) (*Server, error) {
 	if err := srv.setupHTTP(); err != nil {
 		return nil, err
 	}
+	// start ipc server
+	if config.IpcPath != "" {
+		if err := srv.setupIPC(); err != nil {
+			return nil, err
+		}
+	}
+	// start binary server
+	if config.BinaryAddr != "" {
+		if err := srv.setupBinary(); err != nil {
+			return nil, err
+		}
+	}
 	return srv, nil
 }
 
+// Register registers a service under the given name
+func (j *Server) Register(serviceName string, service interface{}) {
+	j.dispatcher.Register(serviceName, service)
+}
+
 func (j *Server) setupHTTP() error {
 	addr, err := net.ResolveTCPAddr("tcp", j.config.Addr)
 	if err != nil {
@@ -71,13 +104,7 @@ func (j *Server) setupHTTP() error {
 		return err
 	}
 
-	mux := http.DefaultServeMux
-	mux.HandleFunc("/", j.handle)
-	mux.HandleFunc("/ws", j.handleWs)
-
-	srv := http.Server{
-		Handler: mux,
-	}
+	srv := j.newHTTPServer()
 	go func() {
 		if err := srv.Serve(lis); err != nil {
 			j.config.Logger.Printf("[ERROR] closed http connection: %v", err)
@@ -86,38 +113,278 @@ func (j *Server) setupHTTP() error {
 	return nil
 }
 
-type wrapWsConn struct {
-	conn *websocket.Conn
+func (j *Server) newHTTPServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", j.handle)
+	mux.HandleFunc("/ws", j.handleWs)
+	mux.HandleFunc("/health", j.handleHealth)
+	mux.HandleFunc("/ready", j.handleReady)
+
+	var handler http.Handler = mux
+	if j.config.EnableH2C {
+		handler = h2c.NewHandler(mux, &http2.Server{
+			IdleTimeout: j.config.HTTPIdleTimeout,
+		})
+	}
+
+	return &http.Server{
+		Handler:        handler,
+		ReadTimeout:    j.config.HTTPReadTimeout,
+		WriteTimeout:   j.config.HTTPWriteTimeout,
+		IdleTimeout:    j.config.HTTPIdleTimeout,
+		MaxHeaderBytes: j.config.HTTPMaxHeaderBytes,
+	}
 }
 
-func (w *wrapWsConn) WriteMessage(b []byte) error {
-	return w.conn.WriteMessage(0, b)
+// wsConn is a websocket connection that implements the Stream interface
+type wsConn struct {
+	conn   *websocket.Conn
+	config *Config
+
+	// gorilla websocket supports only one concurrent writer
+	writeLock sync.Mutex
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newWsConn(conn *websocket.Conn, config *Config) *wsConn {
+	return &wsConn{
+		conn:    conn,
+		config:  config,
+		closeCh: make(chan struct{}),
+	}
+}
+
+func (w *wsConn) ReadMessage() ([]byte, error) {
+	_, message, err := w.conn.ReadMessage()
+	return message, err
+}
+
+func (w *wsConn) WriteMessage(b []byte) error {
+	w.writeLock.Lock()
+	defer w.writeLock.Unlock()
+
+	w.setWriteDeadline()
+	return w.conn.WriteMessage(websocket.TextMessage, b)
+}
+
+func (w *wsConn) Closed() <-chan struct{} {
+	return w.closeCh
+}
+
+func (w *wsConn) close() {
+	w.closeOnce.Do(func() {
+		close(w.closeCh)
+		w.conn.Close()
+	})
+}
+
+// setWriteDeadline sets the deadline for the next write. It also clears
+// any deadline set by the http server before the connection was upgraded.
+func (w *wsConn) setWriteDeadline() {
+	var deadline time.Time
+	if w.config.WsWriteTimeout != 0 {
+		deadline = time.Now().Add(w.config.WsWriteTimeout)
+	}
+	w.conn.SetWriteDeadline(deadline)
+}
+
+func (w *wsConn) extendReadDeadline() {
+	var deadline time.Time
+	if w.config.WsPingInterval != 0 {
+		deadline = time.Now().Add(w.config.WsPingInterval + w.config.WsPongTimeout)
+	}
+	w.conn.SetReadDeadline(deadline)
+}
+
+// keepAlive pings the client periodically. The read deadline is extended
+// every time a pong is received, otherwise the connection times out.
+func (w *wsConn) keepAlive() {
+	if w.config.WsPingInterval == 0 {
+		return
+	}
+
+	w.extendReadDeadline()
+	w.conn.SetPongHandler(func(string) error {
+		w.extendReadDeadline()
+		return nil
+	})
+
+	ticker := time.NewTicker(w.config.WsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.writeLock.Lock()
+			w.setWriteDeadline()
+			err := w.conn.WriteMessage(websocket.PingMessage, nil)
+			w.writeLock.Unlock()
+
+			if err != nil {
+				w.close()
+				return
+			}
+
+		case <-w.closeCh:
+			return
+		}
+	}
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// acquireWsConn reserves a websocket connection slot for the ip
+func (j *Server) acquireWsConn(ip string) bool {
+	j.connsLock.Lock()
+	defer j.connsLock.Unlock()
+
+	if j.config.WsMaxConns != 0 && j.numConns >= j.config.WsMaxConns {
+		return false
+	}
+	if j.config.WsMaxConnsPerIP != 0 && j.connsPerIP[ip] >= j.config.WsMaxConnsPerIP {
+		return false
+	}
+	if j.connsPerIP == nil {
+		j.connsPerIP = map[string]int{}
+	}
+	j.numConns++
+	j.connsPerIP[ip]++
+	return true
+}
+
+func (j *Server) releaseWsConn(ip string) {
+	j.connsLock.Lock()
+	defer j.connsLock.Unlock()
+
+	j.numConns--
+	if j.connsPerIP[ip]--; j.connsPerIP[ip] == 0 {
+		delete(j.connsPerIP, ip)
+	}
 }
 
 func (j *Server) handleWs(w http.ResponseWriter, req *http.Request) {
+	ip := remoteIP(req)
+	if !j.acquireWsConn(ip) {
+		j.config.Logger.Printf("[DEBUG] websocket connection rejected: ip=%s", ip)
+		http.Error(w, "too many websocket connections", http.StatusServiceUnavailable)
+		return
+	}
+	defer j.releaseWsConn(ip)
+
 	c, err := upgrader.Upgrade(w, req, nil)
 	if err != nil {
 		return
 	}
-	defer c.Close()
+	if j.config.WsMaxMessageSize != 0 {
+		c.SetReadLimit(j.config.WsMaxMessageSize)
+	}
+
+	conn := newWsConn(c, j.config)
+	defer conn.close()
+
+	// reset the read deadline of the http server
+	conn.extendReadDeadline()
+
+	go conn.keepAlive()
+
+	j.serveStream(conn)
+}
+
+// streamConn is a persistent connection that reads requests and writes
+// responses as individual messages
+type streamConn interface {
+	Stream
+	ReadMessage() ([]byte, error)
+}
+
+// serveStream handles the requests of the connection until it is closed
+func (j *Server) serveStream(conn streamConn) {
+	if !j.config.OrderedRequests {
+		// handle each request concurrently and reply as soon as it is done
+		for {
+			message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn.WriteMessage(j.handleStreamMessage(message, conn))
+			}()
+		}
+	}
+
+	// the responses are written in the same order as the requests. The
+	// semaphore bounds the number of requests being processed, a request
+	// is not finished until its response is written
+	sem := make(chan struct{}, j.config.MaxPipelinedRequests)
+	pending := make(chan chan []byte, j.config.MaxPipelinedRequests)
+	defer close(pending)
+
+	go func() {
+		for respCh := range pending {
+			conn.WriteMessage(<-respCh)
+			<-sem
+		}
+	}()
 
-	wrapConn := &wrapWsConn{conn: c}
 	for {
-		_, message, err := c.ReadMessage()
+		message, err := conn.ReadMessage()
 		if err != nil {
-			break
+			return
 		}
+
+		sem <- struct{}{}
+		respCh := make(chan []byte, 1)
+		pending <- respCh
+
 		go func() {
-			resp, err := j.dispatcher.Handle(message)
-			if err != nil {
-				wrapConn.WriteMessage(resp)
-			} else {
-				wrapConn.WriteMessage([]byte(err.Error()))
-			}
+			respCh <- j.handleStreamMessage(message, conn)
 		}()
 	}
 }
 
+func (j *Server) handleStreamMessage(message []byte, conn streamConn) []byte {
+	resp, err := j.dispatcher.HandleStream(message, conn)
+	if err != nil {
+		return []byte(err.Error())
+	}
+	return resp
+}
+
+type healthStatus struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func writeHealthStatus(w http.ResponseWriter, code int, status *healthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
+
+// handleHealth reports that the server is alive
+func (j *Server) handleHealth(w http.ResponseWriter, req *http.Request) {
+	writeHealthStatus(w, http.StatusOK, &healthStatus{Status: "ok"})
+}
+
+// handleReady reports whether the node is ready to serve requests
+func (j *Server) handleReady(w http.ResponseWriter, req *http.Request) {
+	if j.config.ReadinessCheck != nil {
+		if err := j.config.ReadinessCheck(); err != nil {
+			writeHealthStatus(w, http.StatusServiceUnavailable, &healthStatus{Status: "unavailable", Reason: err.Error()})
+			return
+		}
+	}
+	writeHealthStatus(w, http.StatusOK, &healthStatus{Status: "ok"})
+}
+
 func (j *Server) handle(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
@@ -0,0 +1,254 @@
+package jsonrpc
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+)
+
+func newTestWsServer(t *testing.T, opts ...ConfigOption) (*Server, string) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	srv := &Server{
+		config:     config,
+		dispatcher: NewDispatcher(),
+	}
+
+	httpSrv := httptest.NewServer(http.HandlerFunc(srv.handleWs))
+	t.Cleanup(httpSrv.Close)
+
+	return srv, "ws" + strings.TrimPrefix(httpSrv.URL, "http")
+}
+
+func TestServer_WsMaxConns(t *testing.T) {
+	_, url := newTestWsServer(t, WithWsMaxConns(0, 1))
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+
+	// a second connection from the same ip is rejected
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	// the slot is released once the first connection is closed
+	conn.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, _, err = websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestServer_WsKeepAlive(t *testing.T) {
+	_, url := newTestWsServer(t, WithWsKeepAlive(100*time.Millisecond, 100*time.Millisecond))
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	pingCh := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pingCh <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go conn.ReadMessage()
+
+	select {
+	case <-pingCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("ping not received")
+	}
+}
+
+func TestServer_WsStreamClosed(t *testing.T) {
+	srv, url := newTestWsServer(t)
+
+	svc := &mockStreamService{streamCh: make(chan Stream, 1)}
+	srv.Register("mock", svc)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id": 1, "method": "mock_subscribe"}`)))
+
+	var stream Stream
+	select {
+	case stream = <-svc.streamCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not received")
+	}
+
+	conn.Close()
+
+	select {
+	case <-stream.Closed():
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed")
+	}
+}
+
+type mockStreamService struct {
+	streamCh chan Stream
+}
+
+func (m *mockStreamService) Subscribe(stream Stream) (string, error) {
+	m.streamCh <- stream
+	return "a", nil
+}
+
+func (m *mockStreamService) Unsubscribe(id string) (bool, error) {
+	return id == "a", nil
+}
+
+func TestServer_WsOrderedRequests(t *testing.T) {
+	srv, url := newTestWsServer(t, WithOrderedRequests(4))
+	srv.Register("mock", &mockSleepService{})
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// the first request takes longer but its response is written first
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id": 1, "method": "mock_sleep", "params": [200]}`)))
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id": 2, "method": "mock_sleep", "params": [0]}`)))
+
+	for _, id := range []float64{1, 2} {
+		var resp Response
+		require.NoError(t, conn.ReadJSON(&resp))
+		require.Equal(t, id, resp.ID)
+	}
+}
+
+func TestServer_IPC(t *testing.T) {
+	config := DefaultConfig()
+	WithIPC(filepath.Join(t.TempDir(), "jsonrpc.ipc"))(config)
+	WithOrderedRequests(1)(config)
+
+	srv := &Server{
+		config:     config,
+		dispatcher: NewDispatcher(),
+	}
+	srv.Register("mock", &mockSleepService{})
+	require.NoError(t, srv.setupIPC())
+
+	conn, err := net.Dial("unix", config.IpcPath)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(`{"id": 1, "method": "mock_sleep", "params": [100]}{"id": 2, "method": "mock_sleep", "params": [0]}`))
+	require.NoError(t, err)
+
+	dec := json.NewDecoder(conn)
+	for _, id := range []float64{1, 2} {
+		var resp Response
+		require.NoError(t, dec.Decode(&resp))
+		require.Equal(t, id, resp.ID)
+	}
+}
+
+type mockSleepService struct {
+}
+
+func (m *mockSleepService) Sleep(ms uint64) (uint64, error) {
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+	return ms, nil
+}
+
+func TestServer_H2C(t *testing.T) {
+	config := DefaultConfig()
+	WithH2C()(config)
+
+	srv := &Server{
+		config:     config,
+		dispatcher: NewDispatcher(),
+	}
+	srv.Register("mock", &mockSleepService{})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	httpSrv := srv.newHTTPServer()
+	go httpSrv.Serve(lis)
+	defer httpSrv.Close()
+
+	// http/2 client with prior knowledge over plain tcp
+	client := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+
+	resp, err := client.Post("http://"+lis.Addr().String(), "application/json", strings.NewReader(`{"id": 1, "method": "mock_sleep", "params": [0]}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, 2, resp.ProtoMajor)
+
+	var res Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	require.Equal(t, "0", string(res.Result))
+}
+
+func TestServer_Health(t *testing.T) {
+	var ready error
+
+	config := DefaultConfig()
+	WithReadinessCheck(func() error {
+		return ready
+	})(config)
+
+	srv := &Server{
+		config:     config,
+		dispatcher: NewDispatcher(),
+	}
+
+	httpSrv := httptest.NewServer(srv.newHTTPServer().Handler)
+	defer httpSrv.Close()
+
+	get := func(path string) (int, *healthStatus) {
+		resp, err := http.Get(httpSrv.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		status := &healthStatus{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(status))
+		return resp.StatusCode, status
+	}
+
+	code, _ := get("/health")
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = get("/ready")
+	require.Equal(t, http.StatusOK, code)
+
+	// the node is behind
+	ready = fmt.Errorf("behind")
+
+	code, status := get("/ready")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "behind", status.Reason)
+
+	// liveness does not depend on the readiness
+	code, _ = get("/health")
+	require.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,143 @@
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/umbracle/ethgo"
+)
+
+// getLogs returns the logs in the range of blocks either from the backend
+// or by scanning the receipts of the blocks. It returns at most limit+1 logs
+// so that the caller can tell whether the limit is exceeded.
+func (e *Eth) getLogs(filter *LogFilter, from, to uint64) ([]*ethgo.Log, error) {
+	if lb, ok := e.b.(LogsBackend); ok {
+		input := &GetLogsInput{
+			From:      from,
+			To:        to,
+			Addresses: filter.Addresses,
+			Topics:    filter.Topics,
+		}
+		return lb.GetLogs(input)
+	}
+	return e.scanLogs(filter, from, to, e.config.LogsMaxResults)
+}
+
+// scanLogs scans the receipts of the blocks in the range. The blocks are skipped
+// with the section index and the logs bloom of the block if available.
+func (e *Eth) scanLogs(filter *LogFilter, from, to uint64, limit uint64) ([]*ethgo.Log, error) {
+	query := newBloomQuery(filter)
+	bb, hasBloom := e.b.(BloomBackend)
+
+	result := []*ethgo.Log{}
+	scan := func(block *ethgo.Block) (bool, error) {
+		logs, err := e.blockLogs(block, filter)
+		if err != nil {
+			return false, err
+		}
+		result = append(result, logs...)
+		return limit != 0 && uint64(len(result)) > limit, nil
+	}
+
+	for num := from; num <= to; num++ {
+		if e.index != nil && len(query) != 0 {
+			if start, matches, ok := e.index.match(query, num); ok {
+				// only scan the candidate blocks of the indexed section
+				end := start + bloomSectionSize - 1
+				if end > to {
+					end = to
+				}
+				for ; num <= end; num++ {
+					if i := num - start; matches[i/8]&(0x80>>(i%8)) == 0 {
+						continue
+					}
+					block, err := e.getBlockForLogs(num)
+					if err != nil {
+						return nil, err
+					}
+					if exceeded, err := scan(block); err != nil || exceeded {
+						return result, err
+					}
+				}
+				num = end
+				continue
+			}
+		}
+
+		block, err := e.getBlockForLogs(num)
+		if err != nil {
+			return nil, err
+		}
+		if hasBloom && !query.matchBloom(bb.LogsBloom(block)) {
+			continue
+		}
+		if exceeded, err := scan(block); err != nil || exceeded {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (e *Eth) getBlockForLogs(num uint64) (*ethgo.Block, error) {
+	block, ok := e.b.GetBlockByNumber(num, false)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+	return block, nil
+}
+
+// blockLogs returns the logs of the block that match the filter
+func (e *Eth) blockLogs(block *ethgo.Block, filter *LogFilter) ([]*ethgo.Log, error) {
+	receipts, err := e.b.GetReceiptsByHash(block.Hash)
+	if err != nil {
+		return nil, err
+	}
+	hashes := txnHashes(block)
+	if len(receipts) != len(hashes) {
+		return nil, fmt.Errorf("block %d has %d transactions but %d receipts", block.Number, len(hashes), len(receipts))
+	}
+
+	result := []*ethgo.Log{}
+	logIndex := uint64(0)
+	for indx, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			l := *log
+			l.Removed = false
+			l.LogIndex = logIndex
+			l.TransactionIndex = uint64(indx)
+			l.TransactionHash = hashes[indx]
+			l.BlockHash = block.Hash
+			l.BlockNumber = block.Number
+			logIndex++
+
+			if filter.Match(&l) {
+				result = append(result, &l)
+			}
+		}
+	}
+	return result, nil
+}
+
+// logsBloom returns the logs bloom of the block either from the
+// backend or from the logs bloom of its receipts
+func (e *Eth) logsBloom(block *ethgo.Block) ([]byte, error) {
+	if bb, ok := e.b.(BloomBackend); ok {
+		if bloom := bb.LogsBloom(block); len(bloom) == bloomSize {
+			return bloom, nil
+		}
+	}
+	receipts, err := e.b.GetReceiptsByHash(block.Hash)
+	if err != nil {
+		return nil, err
+	}
+	bloom := make([]byte, bloomSize)
+	for _, receipt := range receipts {
+		receiptBloom := receipt.LogsBloom
+		if len(receiptBloom) != bloomSize {
+			receiptBloom = createBloom(receipt.Logs)
+		}
+		for i := range bloom {
+			bloom[i] |= receiptBloom[i]
+		}
+	}
+	return bloom, nil
+}
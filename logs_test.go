@@ -0,0 +1,207 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+)
+
+type mockChainLogs struct {
+	nullBlockchainInterface
+	head     uint64
+	fork     byte
+	logs     map[uint64][]*ethgo.Log
+	receipts int
+}
+
+func (m *mockChainLogs) addLogs(num uint64, logs ...*ethgo.Log) {
+	if m.logs == nil {
+		m.logs = map[uint64][]*ethgo.Log{}
+	}
+	m.logs[num] = append(m.logs[num], logs...)
+}
+
+func (m *mockChainLogs) blockHash(num uint64) ethgo.Hash {
+	hash := ethgo.Hash{m.fork}
+	binary.BigEndian.PutUint64(hash[24:], num+1)
+	return hash
+}
+
+func (m *mockChainLogs) Header() *ethgo.Block {
+	return &ethgo.Block{Number: m.head, Hash: m.blockHash(m.head)}
+}
+
+func (m *mockChainLogs) GetBlockByNumber(num uint64, full bool) (*ethgo.Block, bool) {
+	if num > m.head {
+		return nil, false
+	}
+	return &ethgo.Block{Number: num, Hash: m.blockHash(num), TransactionsHashes: []ethgo.Hash{hash1}}, true
+}
+
+func (m *mockChainLogs) GetReceiptsByHash(hash ethgo.Hash) ([]*ethgo.Receipt, error) {
+	m.receipts++
+	num := binary.BigEndian.Uint64(hash[24:]) - 1
+	receipt := &ethgo.Receipt{
+		Logs: m.logs[num],
+	}
+	return []*ethgo.Receipt{receipt}, nil
+}
+
+type mockChainBlooms struct {
+	mockChainLogs
+}
+
+func (m *mockChainBlooms) LogsBloom(block *ethgo.Block) []byte {
+	return createBloom(m.logs[block.Number])
+}
+
+func TestEth_ScanLogs(t *testing.T) {
+	b := &mockChainLogs{head: 20}
+	b.addLogs(5, &ethgo.Log{Address: addr1, Topics: []ethgo.Hash{hash1}})
+	b.addLogs(5, &ethgo.Log{Address: addr2})
+	b.addLogs(10, &ethgo.Log{Address: addr1, Topics: []ethgo.Hash{hash2}})
+
+	eth := NewEth(b)
+
+	logs, err := eth.GetLogs(&LogFilter{fromBlock: 0, toBlock: 20, Addresses: []ethgo.Address{addr1}})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+
+	// the block and transaction fields are filled
+	assert.Equal(t, logs[0].BlockNumber, uint64(5))
+	assert.Equal(t, logs[0].BlockHash, b.blockHash(5))
+	assert.Equal(t, logs[0].TransactionHash, hash1)
+	assert.Equal(t, logs[1].BlockNumber, uint64(10))
+
+	// the log index is the position in the block
+	logs, err = eth.GetLogs(&LogFilter{fromBlock: 5, toBlock: 5, Addresses: []ethgo.Address{addr2}})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, logs[0].LogIndex, uint64(1))
+
+	// the limit of results is checked while scanning
+	eth = NewEth(b, WithLogsLimits(100, 1))
+
+	_, err = eth.GetLogs(&LogFilter{fromBlock: 0, toBlock: 20})
+	assert.Equal(t, err.Error(), "query returned more than 1 results. Try with this block range [0x0, 0x4].")
+
+	// the range is beyond the head
+	_, err = eth.GetLogs(&LogFilter{fromBlock: 15, toBlock: 25})
+	assert.Error(t, err)
+}
+
+func TestEth_ScanLogs_Bloom(t *testing.T) {
+	b := &mockChainBlooms{}
+	b.head = 20
+	b.addLogs(5, &ethgo.Log{Address: addr1})
+
+	eth := NewEth(b)
+
+	logs, err := eth.GetLogs(&LogFilter{fromBlock: 0, toBlock: 20, Addresses: []ethgo.Address{addr1}})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+
+	// only the receipts of the block that matches the bloom are read
+	assert.Equal(t, b.receipts, 1)
+}
+
+func TestBloomQuery(t *testing.T) {
+	bloom := createBloom([]*ethgo.Log{
+		{Address: addr1, Topics: []ethgo.Hash{hash1, hash2}},
+	})
+
+	cases := []struct {
+		filter *LogFilter
+		match  bool
+	}{
+		{&LogFilter{}, true},
+		{&LogFilter{Addresses: []ethgo.Address{addr1}}, true},
+		{&LogFilter{Addresses: []ethgo.Address{addr2}}, false},
+		{&LogFilter{Addresses: []ethgo.Address{addr2, addr1}}, true},
+		{&LogFilter{Topics: [][]ethgo.Hash{nil, {hash2}}}, true},
+		{&LogFilter{Topics: [][]ethgo.Hash{{hash1}, {hash3}}}, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, newBloomQuery(c.filter).matchBloom(bloom), c.match)
+	}
+
+	// the unknown bloom matches any query
+	assert.True(t, newBloomQuery(&LogFilter{Addresses: []ethgo.Address{addr2}}).matchBloom(nil))
+}
+
+func TestBloomIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bloom-index")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b := &mockChainLogs{head: bloomSectionSize + bloomConfirmations - 2}
+	b.addLogs(10, &ethgo.Log{Address: addr1})
+	b.addLogs(4000, &ethgo.Log{Address: addr1, Topics: []ethgo.Hash{hash1}})
+	b.addLogs(4100, &ethgo.Log{Address: addr1})
+
+	eth := NewEth(b)
+	index := newBloomIndex(nil, dir, b, eth.logsBloom)
+	eth.index = index
+
+	// the section is not confirmed yet
+	assert.NoError(t, index.indexSections())
+	_, _, ok := index.match(bloomQuery{}, 0)
+	assert.False(t, ok)
+
+	b.head++
+	assert.NoError(t, index.indexSections())
+	assert.Equal(t, uint64(1), index.nextSection)
+
+	start, matches, ok := index.match(newBloomQuery(&LogFilter{Addresses: []ethgo.Address{addr1}}), 100)
+	assert.True(t, ok)
+	assert.Equal(t, start, uint64(0))
+	assert.Equal(t, matches[10/8], byte(0x80>>(10%8)))
+
+	// the section index skips the receipts of the blocks without logs
+	b.receipts = 0
+	logs, err := eth.GetLogs(&LogFilter{fromBlock: 0, toBlock: 4200, Addresses: []ethgo.Address{addr1}})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 3)
+	assert.Equal(t, b.receipts, 2+4200-bloomSectionSize+1)
+
+	logs, err = eth.GetLogs(&LogFilter{fromBlock: 20, toBlock: 4095, Topics: [][]ethgo.Hash{{hash1}}})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, logs[0].BlockNumber, uint64(4000))
+
+	// the section is removed after a reorg
+	b.fork = 1
+	_, _, ok = index.match(bloomQuery{}, 0)
+	assert.False(t, ok)
+
+	_, err = os.Stat(index.sectionPath(0))
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, uint64(0), index.nextSection)
+}
+
+func TestBloomIndex_Close(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bloom-index")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b := &mockChainLogs{}
+	eth := NewEth(b, WithLogIndex(dir), WithContext(ctx))
+	assert.NotNil(t, eth.index)
+
+	// the index stops with the context
+	cancel()
+
+	select {
+	case <-eth.index.closeCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("bloom index not closed")
+	}
+}
@@ -68,6 +68,9 @@ func (l *LogFilter) UnmarshalJSON(data []byte) error {
 	}
 
 	l.BlockHash = obj.BlockHash
+	if l.BlockHash != nil && (obj.FromBlock != "" || obj.ToBlock != "") {
+		return fmt.Errorf("cannot specify both blockHash and fromBlock/toBlock, choose one or the other")
+	}
 
 	if obj.FromBlock == "" {
 		l.fromBlock = LatestBlockNumber
@@ -184,14 +187,48 @@ func (l *LogFilter) Match(log *ethgo.Log) bool {
 	return true
 }
 
+// matchBlock returns whether the block number is in the range of the filter.
+// Only block numbers and the earliest tag limit the range, the tags that follow
+// the head of the chain (latest, pending, safe and finalized) leave it open.
+func (l *LogFilter) matchBlock(num uint64) bool {
+	if l.fromBlock >= 0 && num < uint64(l.fromBlock) {
+		return false
+	}
+	if l.toBlock == EarliestBlockNumber && num != 0 {
+		return false
+	}
+	if l.toBlock >= 0 && num > uint64(l.toBlock) {
+		return false
+	}
+	return true
+}
+
 const (
-	PendingBlockNumber  = BlockNumber(-3)
-	LatestBlockNumber   = BlockNumber(-2)
-	EarliestBlockNumber = BlockNumber(-1)
+	FinalizedBlockNumber = BlockNumber(-5)
+	SafeBlockNumber      = BlockNumber(-4)
+	PendingBlockNumber   = BlockNumber(-3)
+	LatestBlockNumber    = BlockNumber(-2)
+	EarliestBlockNumber  = BlockNumber(-1)
 )
 
 type BlockNumber int64
 
+func (b BlockNumber) String() string {
+	switch b {
+	case FinalizedBlockNumber:
+		return "finalized"
+	case SafeBlockNumber:
+		return "safe"
+	case PendingBlockNumber:
+		return "pending"
+	case LatestBlockNumber:
+		return "latest"
+	case EarliestBlockNumber:
+		return "earliest"
+	}
+	return fmt.Sprintf("0x%x", int64(b))
+}
+
 func stringToBlockNumber(str string) (BlockNumber, error) {
 	if str == "" {
 		return 0, fmt.Errorf("value is empty")
@@ -205,6 +242,10 @@ func stringToBlockNumber(str string) (BlockNumber, error) {
 		return LatestBlockNumber, nil
 	case "earliest":
 		return EarliestBlockNumber, nil
+	case "safe":
+		return SafeBlockNumber, nil
+	case "finalized":
+		return FinalizedBlockNumber, nil
 	}
 
 	n, err := parseUint64orHex(&str)
@@ -224,6 +265,67 @@ func (b *BlockNumber) UnmarshalJSON(buffer []byte) error {
 	return nil
 }
 
+// BlockNumberOrHash references a block either by number (or tag) or by
+// hash as specified in EIP-1898. The zero value references the latest block.
+type BlockNumberOrHash struct {
+	BlockNumber      *BlockNumber
+	BlockHash        *ethgo.Hash
+	RequireCanonical bool
+}
+
+// BlockNumberOrHashWithNumber references a block by number
+func BlockNumberOrHashWithNumber(num BlockNumber) BlockNumberOrHash {
+	return BlockNumberOrHash{BlockNumber: &num}
+}
+
+// BlockNumberOrHashWithHash references a block by hash
+func BlockNumberOrHashWithHash(hash ethgo.Hash, canonical bool) BlockNumberOrHash {
+	return BlockNumberOrHash{BlockHash: &hash, RequireCanonical: canonical}
+}
+
+// UnmarshalJSON decodes either a block number, a block hash or
+// an EIP-1898 object with the blockNumber or blockHash fields
+func (b *BlockNumberOrHash) UnmarshalJSON(data []byte) error {
+	var obj struct {
+		BlockNumber      *BlockNumber `json:"blockNumber"`
+		BlockHash        *ethgo.Hash  `json:"blockHash"`
+		RequireCanonical bool         `json:"requireCanonical"`
+	}
+	if len(data) != 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		if obj.BlockNumber != nil && obj.BlockHash != nil {
+			return fmt.Errorf("cannot specify both blockHash and blockNumber")
+		}
+		if obj.BlockNumber == nil && obj.BlockHash == nil {
+			return fmt.Errorf("either blockHash or blockNumber must be specified")
+		}
+		b.BlockNumber = obj.BlockNumber
+		b.BlockHash = obj.BlockHash
+		b.RequireCanonical = obj.RequireCanonical
+		return nil
+	}
+
+	str := strings.Trim(string(data), "\"")
+	if len(str) == 66 {
+		// block hash
+		hash := ethgo.Hash{}
+		if err := hash.UnmarshalText([]byte(str)); err != nil {
+			return err
+		}
+		b.BlockHash = &hash
+		return nil
+	}
+
+	num, err := stringToBlockNumber(str)
+	if err != nil {
+		return err
+	}
+	b.BlockNumber = &num
+	return nil
+}
+
 func parseUint64orHex(val *string) (uint64, error) {
 	if val == nil {
 		return 0, nil
@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -118,6 +119,23 @@ func TestFilterDecode(t *testing.T) {
 				toBlock:   LatestBlockNumber,
 			},
 		},
+		{
+			`{
+				"blockHash": "` + hash1.String() + `",
+				"fromBlock": "0x1"
+			}`,
+			nil,
+		},
+		{
+			`{
+				"fromBlock": "safe",
+				"toBlock": "finalized"
+			}`,
+			&LogFilter{
+				fromBlock: SafeBlockNumber,
+				toBlock:   FinalizedBlockNumber,
+			},
+		},
 	}
 
 	for indx, c := range cases {
@@ -225,3 +243,80 @@ func TestFilterMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterMatchBlock(t *testing.T) {
+	cases := []struct {
+		from, to BlockNumber
+		num      uint64
+		match    bool
+	}{
+		{LatestBlockNumber, LatestBlockNumber, 10, true},
+		{EarliestBlockNumber, FinalizedBlockNumber, 10, true},
+		{5, LatestBlockNumber, 10, true},
+		{5, LatestBlockNumber, 4, false},
+		{5, 8, 10, false},
+		{EarliestBlockNumber, EarliestBlockNumber, 10, false},
+	}
+
+	for indx, c := range cases {
+		filter := &LogFilter{fromBlock: c.from, toBlock: c.to}
+		if filter.matchBlock(c.num) != c.match {
+			t.Fatalf("bad %d", indx)
+		}
+	}
+}
+
+func TestBlockNumberOrHashDecode(t *testing.T) {
+	latest := LatestBlockNumber
+	num := BlockNumber(16)
+
+	cases := []struct {
+		str string
+		res *BlockNumberOrHash
+	}{
+		{
+			`"latest"`,
+			&BlockNumberOrHash{BlockNumber: &latest},
+		},
+		{
+			`"0x10"`,
+			&BlockNumberOrHash{BlockNumber: &num},
+		},
+		{
+			`"` + hash1.String() + `"`,
+			&BlockNumberOrHash{BlockHash: &hash1},
+		},
+		{
+			`{"blockNumber": "0x10"}`,
+			&BlockNumberOrHash{BlockNumber: &num},
+		},
+		{
+			`{"blockHash": "` + hash1.String() + `", "requireCanonical": true}`,
+			&BlockNumberOrHash{BlockHash: &hash1, RequireCanonical: true},
+		},
+		{
+			`{"blockHash": "` + hash1.String() + `", "blockNumber": "0x10"}`,
+			nil,
+		},
+		{
+			`{}`,
+			nil,
+		},
+	}
+
+	for indx, c := range cases {
+		res := &BlockNumberOrHash{}
+		err := json.Unmarshal([]byte(c.str), res)
+		if err != nil && c.res != nil {
+			t.Fatal(err)
+		}
+		if err == nil && c.res == nil {
+			t.Fatal("it should fail")
+		}
+		if c.res != nil {
+			if !reflect.DeepEqual(res, c.res) {
+				t.Fatalf("bad %d", indx)
+			}
+		}
+	}
+}
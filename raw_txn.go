@@ -0,0 +1,117 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// decodeRawTxn decodes the signed transaction sent with eth_sendRawTransaction
+// and validates it before it is added to the transaction pool
+func (e *Eth) decodeRawTxn(raw []byte) (*ethgo.Transaction, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty transaction")
+	}
+
+	txn := &ethgo.Transaction{}
+	if err := txn.UnmarshalRLP(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %v", err)
+	}
+	// the encoding must be canonical and without trailing bytes
+	// since the hash of the transaction is the hash of the raw bytes
+	enc, err := txn.MarshalRLPTo(nil)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(enc, raw) {
+		return nil, fmt.Errorf("failed to decode transaction: non-canonical encoding")
+	}
+
+	if len(txn.R) == 0 || len(txn.S) == 0 {
+		return nil, fmt.Errorf("transaction is not signed")
+	}
+
+	chainID := e.b.ChainID()
+
+	v := new(big.Int).SetBytes(txn.V)
+	if txn.Type == ethgo.TransactionLegacy {
+		if v.Cmp(big.NewInt(27)) == 0 || v.Cmp(big.NewInt(28)) == 0 {
+			if !e.config.AllowUnprotectedTxs {
+				return nil, fmt.Errorf("only replay-protected (EIP-155) transactions allowed over RPC")
+			}
+			// pre EIP-155 signature
+			chainID = 0
+			v.Sub(v, big.NewInt(27))
+		} else {
+			// v = recovery id + 35 + chain id * 2
+			if v.Cmp(big.NewInt(35)) < 0 {
+				return nil, fmt.Errorf("invalid signature value v")
+			}
+			txnChainID := new(big.Int).Sub(v, big.NewInt(35))
+			txnChainID.Rsh(txnChainID, 1)
+			if !txnChainID.IsUint64() || txnChainID.Uint64() != chainID {
+				return nil, fmt.Errorf("invalid chain id (have=%s, want=%d)", txnChainID, chainID)
+			}
+			v.Sub(v, new(big.Int).SetUint64(35+chainID*2))
+		}
+	} else {
+		if !txn.ChainID.IsUint64() || txn.ChainID.Uint64() != chainID {
+			return nil, fmt.Errorf("invalid chain id (have=%s, want=%d)", txn.ChainID, chainID)
+		}
+	}
+	if v.Cmp(big.NewInt(1)) > 0 {
+		return nil, fmt.Errorf("invalid signature value v")
+	}
+
+	if txn.Type == ethgo.TransactionDynamicFee && txn.MaxPriorityFeePerGas.Cmp(txn.MaxFeePerGas) > 0 {
+		return nil, fmt.Errorf("max priority fee per gas higher than max fee per gas")
+	}
+	if err := e.checkTxnFee(txn); err != nil {
+		return nil, err
+	}
+
+	// recover the sender to validate the signature
+	sig := make([]byte, 65)
+	if len(txn.R) > 32 || len(txn.S) > 32 {
+		return nil, fmt.Errorf("invalid signature values")
+	}
+	copy(sig[32-len(txn.R):32], txn.R)
+	copy(sig[64-len(txn.S):64], txn.S)
+	sig[64] = byte(v.Uint64())
+
+	from, err := wallet.Ecrecover(txnSigningHash(txn, chainID), sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender: %v", err)
+	}
+	txn.From = from
+
+	return txn, nil
+}
+
+// checkTxnFee checks that the maximum fee paid by the transaction is below the configured cap
+func (e *Eth) checkTxnFee(txn *ethgo.Transaction) error {
+	feeCap := e.config.TxFeeCap
+	if feeCap == nil || feeCap.Sign() == 0 {
+		return nil
+	}
+
+	gasPrice := new(big.Int).SetUint64(txn.GasPrice)
+	if txn.Type == ethgo.TransactionDynamicFee {
+		gasPrice = txn.MaxFeePerGas
+	}
+	fee := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(txn.Gas))
+	if fee.Cmp(feeCap) > 0 {
+		return fmt.Errorf("tx fee (%s ether) exceeds the configured cap (%s ether)", toEther(fee), toEther(feeCap))
+	}
+	return nil
+}
+
+// toEther formats the wei amount in ether without losing precision
+func toEther(wei *big.Int) string {
+	str := new(big.Rat).SetFrac(wei, big.NewInt(1e18)).FloatString(18)
+	return strings.TrimSuffix(strings.TrimRight(str, "0"), ".")
+}
@@ -0,0 +1,120 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+// signedRawTxn signs the transaction with a new key and returns its rlp encoding
+func signedRawTxn(t *testing.T, txn *ethgo.Transaction, chainID uint64) []byte {
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	txn.From = key.Address()
+	if txn.Value == nil {
+		txn.Value = new(big.Int)
+	}
+	if txn.Type != ethgo.TransactionLegacy {
+		txn.ChainID = new(big.Int).SetUint64(chainID)
+	}
+	assert.NoError(t, signTxn(NewDevSigner(key), txn, chainID))
+
+	raw, err := txn.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+	return raw
+}
+
+type mockRawTxnStore struct {
+	mockStoreTxn
+}
+
+func (m *mockRawTxnStore) ChainID() uint64 {
+	return 5
+}
+
+func TestEth_DecodeRawTxn(t *testing.T) {
+	eth := NewEth(&mockRawTxnStore{}, WithTxFeeCap(big.NewInt(1000000)))
+
+	legacy := func() *ethgo.Transaction {
+		return &ethgo.Transaction{To: &addr1, Gas: 21000, GasPrice: 10}
+	}
+	dynamic := func() *ethgo.Transaction {
+		return &ethgo.Transaction{
+			Type:                 ethgo.TransactionDynamicFee,
+			To:                   &addr1,
+			Gas:                  21000,
+			MaxFeePerGas:         big.NewInt(10),
+			MaxPriorityFeePerGas: big.NewInt(1),
+		}
+	}
+
+	// legacy transaction with replay protection
+	txn := legacy()
+	res, err := eth.decodeRawTxn(signedRawTxn(t, txn, 5))
+	assert.NoError(t, err)
+	assert.Equal(t, res.From, txn.From)
+	assert.Equal(t, res.Hash, txn.Hash)
+
+	// typed transaction
+	txn = dynamic()
+	res, err = eth.decodeRawTxn(signedRawTxn(t, txn, 5))
+	assert.NoError(t, err)
+	assert.Equal(t, res.From, txn.From)
+
+	// wrong chain id
+	_, err = eth.decodeRawTxn(signedRawTxn(t, legacy(), 6))
+	assert.EqualError(t, err, "invalid chain id (have=6, want=5)")
+
+	_, err = eth.decodeRawTxn(signedRawTxn(t, dynamic(), 6))
+	assert.EqualError(t, err, "invalid chain id (have=6, want=5)")
+
+	// trailing bytes
+	raw := signedRawTxn(t, legacy(), 5)
+	_, err = eth.decodeRawTxn(append(raw, 0x1))
+	assert.Error(t, err)
+
+	// unsigned transaction
+	txn = legacy()
+	txn.Value = new(big.Int)
+	raw, err = txn.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+	_, err = eth.decodeRawTxn(raw)
+	assert.EqualError(t, err, "transaction is not signed")
+
+	// the fee is over the cap
+	txn = legacy()
+	txn.GasPrice = 100
+	_, err = eth.decodeRawTxn(signedRawTxn(t, txn, 5))
+	assert.EqualError(t, err, "tx fee (0.0000000000021 ether) exceeds the configured cap (0.000000000001 ether)")
+
+	// the priority fee is higher than the fee cap
+	txn = dynamic()
+	txn.MaxPriorityFeePerGas = big.NewInt(11)
+	_, err = eth.decodeRawTxn(signedRawTxn(t, txn, 5))
+	assert.Error(t, err)
+}
+
+func TestEth_DecodeRawTxn_Unprotected(t *testing.T) {
+	// the legacy transaction is signed without chain id
+	txn := &ethgo.Transaction{To: &addr1, Gas: 21000, GasPrice: 10}
+	raw := signedRawTxn(t, txn, 0)
+	assert.True(t, new(big.Int).SetBytes(txn.V).Uint64()-27 <= 1)
+
+	_, err := NewEth(&mockRawTxnStore{}).decodeRawTxn(raw)
+	assert.EqualError(t, err, "only replay-protected (EIP-155) transactions allowed over RPC")
+
+	res, err := NewEth(&mockRawTxnStore{}, WithUnprotectedTxs(true)).decodeRawTxn(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, res.From, txn.From)
+}
+
+func TestToEther(t *testing.T) {
+	assert.Equal(t, "0", toEther(big.NewInt(0)))
+	assert.Equal(t, "1", toEther(big.NewInt(1e18)))
+	assert.Equal(t, "1.5", toEther(big.NewInt(15e17)))
+	assert.Equal(t, "0.000000000000000001", toEther(big.NewInt(1)))
+}
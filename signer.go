@@ -0,0 +1,202 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/wallet"
+	"github.com/umbracle/fastrlp"
+)
+
+// Signer signs transactions and messages with the accounts managed by the node
+type Signer interface {
+	// Accounts returns the addresses of the accounts of the signer
+	Accounts() []ethgo.Address
+
+	// SignHash signs the hash with the key of the account. The signature
+	// is in the [R || S || V] format where V is 0 or 1.
+	SignHash(addr ethgo.Address, hash []byte) ([]byte, error)
+}
+
+// LocalSigner is a signer with the keys of the accounts in memory
+type LocalSigner struct {
+	lock sync.RWMutex
+	keys map[ethgo.Address]*wallet.Key
+	accs []ethgo.Address
+}
+
+// NewDevSigner creates a signer with the given in-memory keys
+func NewDevSigner(keys ...*wallet.Key) *LocalSigner {
+	s := &LocalSigner{
+		keys: map[ethgo.Address]*wallet.Key{},
+		accs: []ethgo.Address{},
+	}
+	for _, key := range keys {
+		s.AddKey(key)
+	}
+	return s
+}
+
+// NewKeystoreSigner creates a signer with the keys of the encrypted
+// json (v3) keystore files in the directory
+func NewKeystoreSigner(dir string, password string) (*LocalSigner, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewDevSigner()
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid(content) {
+			// not a keystore file
+			continue
+		}
+		key, err := wallet.NewJSONWalletFromContent(content, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt keystore file %s: %v", path, err)
+		}
+		s.AddKey(key)
+	}
+	return s, nil
+}
+
+// AddKey adds the key of an account to the signer
+func (s *LocalSigner) AddKey(key *wallet.Key) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.keys[key.Address()]; ok {
+		return
+	}
+	s.keys[key.Address()] = key
+	s.accs = append(s.accs, key.Address())
+}
+
+// Accounts implements the Signer interface
+func (s *LocalSigner) Accounts() []ethgo.Address {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return append([]ethgo.Address{}, s.accs...)
+}
+
+// SignHash implements the Signer interface
+func (s *LocalSigner) SignHash(addr ethgo.Address, hash []byte) ([]byte, error) {
+	s.lock.RLock()
+	key, ok := s.keys[addr]
+	s.lock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown account %s", addr)
+	}
+	return key.Sign(hash)
+}
+
+// signTxn signs the transaction of the sender with the chain id (EIP-155). Legacy
+// transactions are signed without replay protection if the chain id is zero.
+func signTxn(signer Signer, txn *ethgo.Transaction, chainID uint64) error {
+	sig, err := signer.SignHash(txn.From, txnSigningHash(txn, chainID))
+	if err != nil {
+		return err
+	}
+
+	v := new(big.Int).SetUint64(uint64(sig[64]))
+	if txn.Type == ethgo.TransactionLegacy {
+		if chainID != 0 {
+			v.Add(v, new(big.Int).SetUint64(35+chainID*2))
+		} else {
+			// without replay protection
+			v.Add(v, big.NewInt(27))
+		}
+	}
+	txn.V = v.Bytes()
+	txn.R = new(big.Int).SetBytes(sig[:32]).Bytes()
+	txn.S = new(big.Int).SetBytes(sig[32:64]).Bytes()
+
+	hash, err := txn.GetHash()
+	if err != nil {
+		return err
+	}
+	txn.Hash = hash
+	return nil
+}
+
+// txnSigningHash returns the hash of the transaction signed by the sender
+func txnSigningHash(txn *ethgo.Transaction, chainID uint64) []byte {
+	a := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(a)
+
+	v := a.NewArray()
+	if txn.Type != ethgo.TransactionLegacy {
+		v.Set(a.NewUint(chainID))
+	}
+	v.Set(a.NewUint(txn.Nonce))
+	if txn.Type == ethgo.TransactionDynamicFee {
+		v.Set(a.NewBigInt(bigOrZero(txn.MaxPriorityFeePerGas)))
+		v.Set(a.NewBigInt(bigOrZero(txn.MaxFeePerGas)))
+	} else {
+		v.Set(a.NewUint(txn.GasPrice))
+	}
+	v.Set(a.NewUint(txn.Gas))
+	if txn.To == nil {
+		v.Set(a.NewNull())
+	} else {
+		v.Set(a.NewCopyBytes((*txn.To)[:]))
+	}
+	v.Set(a.NewBigInt(bigOrZero(txn.Value)))
+	v.Set(a.NewCopyBytes(txn.Input))
+
+	if txn.Type == ethgo.TransactionLegacy {
+		if chainID != 0 {
+			// EIP-155
+			v.Set(a.NewUint(chainID))
+			v.Set(a.NewUint(0))
+			v.Set(a.NewUint(0))
+		}
+		return ethgo.Keccak256(v.MarshalTo(nil))
+	}
+
+	accessList := a.NewArray()
+	for _, entry := range txn.AccessList {
+		storage := a.NewArray()
+		for _, slot := range entry.Storage {
+			storage.Set(a.NewCopyBytes(slot[:]))
+		}
+		item := a.NewArray()
+		item.Set(a.NewCopyBytes(entry.Address[:]))
+		item.Set(storage)
+		accessList.Set(item)
+	}
+	v.Set(accessList)
+
+	return ethgo.Keccak256(append([]byte{byte(txn.Type)}, v.MarshalTo(nil)...))
+}
+
+// signMessageHash returns the hash of a message signed with eth_sign (EIP-191)
+func signMessageHash(data []byte) []byte {
+	msg := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(data))
+	return ethgo.Keccak256(append([]byte(msg), data...))
+}
+
+// toRPCSignature returns the signature with the V value used by the
+// eth_sign endpoints (27 or 28)
+func toRPCSignature(sig []byte) argBytes {
+	res := append([]byte{}, sig...)
+	res[64] += 27
+	return argBytes(res)
+}
@@ -0,0 +1,267 @@
+package jsonrpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/keystore"
+	"github.com/umbracle/ethgo/wallet"
+)
+
+type mockSignStore struct {
+	mockEstimateStore
+	nonce uint64
+	raw   []byte
+}
+
+func (m *mockSignStore) ChainID() uint64 {
+	return 5
+}
+
+func (m *mockSignStore) GetPendingNonce(addr ethgo.Address) (uint64, bool) {
+	return m.nonce, true
+}
+
+func (m *mockSignStore) AddTx(raw []byte) (ethgo.Hash, error) {
+	m.raw = raw
+	return ethgo.BytesToHash(ethgo.Keccak256(raw)), nil
+}
+
+type mockSignLondonStore struct {
+	mockSignStore
+}
+
+func (m *mockSignLondonStore) BaseFee(block *ethgo.Block) *big.Int {
+	return big.NewInt(1)
+}
+
+// recoverTxnSender returns the sender of the signed transaction
+func recoverTxnSender(t *testing.T, txn *ethgo.Transaction, chainID uint64) ethgo.Address {
+	v := new(big.Int).SetBytes(txn.V).Uint64()
+	if txn.Type == ethgo.TransactionLegacy {
+		v -= 35 + chainID*2
+	}
+	sig := make([]byte, 65)
+	copy(sig[32-len(txn.R):32], txn.R)
+	copy(sig[64-len(txn.S):64], txn.S)
+	sig[64] = byte(v)
+
+	addr, err := wallet.Ecrecover(txnSigningHash(txn, chainID), sig)
+	assert.NoError(t, err)
+	return addr
+}
+
+func TestEth_SendTransaction(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	b := &mockSignStore{nonce: 5}
+	b.required = 53000
+
+	eth := NewEth(b, WithSigner(NewDevSigner(key)))
+
+	accounts, err := eth.Accounts()
+	assert.NoError(t, err)
+	assert.Equal(t, accounts, []ethgo.Address{key.Address()})
+
+	from := key.Address()
+	hash, err := eth.SendTransaction(&txnArgs{From: &from, To: &addr1, Value: argBytesPtr([]byte{0x1})})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(hash), ethgo.Keccak256(b.raw))
+
+	txn := &ethgo.Transaction{}
+	assert.NoError(t, txn.UnmarshalRLP(b.raw))
+
+	// the defaults are filled
+	assert.Equal(t, txn.Nonce, uint64(5))
+	assert.Equal(t, txn.Gas, uint64(53000))
+	assert.Equal(t, txn.GasPrice, uint64(1))
+
+	// the signature is replay protected with the chain id
+	assert.True(t, new(big.Int).SetBytes(txn.V).Uint64()-35-5*2 <= 1)
+	assert.Equal(t, recoverTxnSender(t, txn, 5), key.Address())
+
+	signer := wallet.NewEIP155Signer(5)
+	sender, err := signer.RecoverSender(txn)
+	assert.NoError(t, err)
+	assert.Equal(t, sender, key.Address())
+
+	// the account is not managed by the signer
+	_, err = eth.SendTransaction(&txnArgs{From: &addr1, To: &addr2, Gas: argUintPtr(21000)})
+	assert.Error(t, err)
+
+	// the transaction is empty
+	_, err = eth.SendTransaction(nil)
+	assert.EqualError(t, err, "transaction is empty")
+
+	_, err = eth.SignTransaction(nil)
+	assert.EqualError(t, err, "transaction is empty")
+}
+
+func TestEth_SignTransaction(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	b := &mockSignLondonStore{}
+	eth := NewEth(b, WithSigner(NewDevSigner(key)))
+
+	from := key.Address()
+	res, err := eth.SignTransaction(&txnArgs{
+		From:                 &from,
+		To:                   &addr1,
+		Gas:                  argUintPtr(21000),
+		MaxFeePerGas:         argBytesPtr([]byte{0x10}),
+		MaxPriorityFeePerGas: argBytesPtr([]byte{0x1}),
+		AccessList:           []rpcAccessEntry{{Address: addr2, StorageKeys: []ethgo.Hash{hash1}}},
+	})
+	assert.NoError(t, err)
+
+	// the transaction is not sent
+	assert.Nil(t, b.raw)
+
+	txn := &ethgo.Transaction{}
+	assert.NoError(t, txn.UnmarshalRLP(res.Raw))
+	assert.Equal(t, txn.Type, ethgo.TransactionDynamicFee)
+	assert.Equal(t, txn.Hash, res.Tx.Hash)
+	assert.Equal(t, recoverTxnSender(t, txn, 5), key.Address())
+}
+
+func TestEth_Sign(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	// the node does not have a signer
+	_, err = NewEth(&mockSignStore{}).Sign(key.Address(), argBytes("hello"))
+	assert.EqualError(t, err, "signing is not supported")
+
+	eth := NewEth(&mockSignStore{}, WithSigner(NewDevSigner(key)))
+
+	sig, err := eth.Sign(key.Address(), argBytes("hello"))
+	assert.NoError(t, err)
+	assert.Len(t, sig, 65)
+	assert.True(t, sig[64] == 27 || sig[64] == 28)
+
+	sig[64] -= 27
+	addr, err := wallet.Ecrecover(signMessageHash([]byte("hello")), sig)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, key.Address())
+}
+
+// typedDataMail is the example of EIP-712
+var typedDataMail = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestEth_SignTypedData(t *testing.T) {
+	data := &typedData{}
+	assert.NoError(t, json.Unmarshal([]byte(typedDataMail), data))
+
+	assert.Equal(t, data.encodeType("Mail"), "Mail(Person from,Person to,string contents)Person(string name,address wallet)")
+
+	hash, err := data.hash()
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(hash), "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2")
+
+	key, err := wallet.NewWalletFromPrivKey(ethgo.Keccak256([]byte("cow")))
+	assert.NoError(t, err)
+
+	eth := NewEth(&mockSignStore{}, WithSigner(NewDevSigner(key)))
+
+	sig, err := eth.SignTypedData_v4(key.Address(), data)
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sig), "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c")
+}
+
+func TestTypedData_EncodeValue(t *testing.T) {
+	data := &typedData{}
+
+	cases := []struct {
+		typ string
+		val interface{}
+		err bool
+	}{
+		{"uint8", json.Number("255"), false},
+		{"uint8", json.Number("256"), true},
+		{"uint256", "0x10", false},
+		{"int8", json.Number("-128"), false},
+		{"int8", json.Number("128"), true},
+		{"bytes4", "0x01020304", false},
+		{"bytes4", "0x0102030405", true},
+		{"bool", "true", true},
+		{"uint256[2]", []interface{}{json.Number("1")}, true},
+		{"Unknown", "0x1", true},
+	}
+	for _, c := range cases {
+		_, err := data.encodeValue(c.typ, c.val)
+		assert.Equal(t, err != nil, c.err, c.typ)
+	}
+
+	// negative numbers are encoded with two's complement
+	res, err := data.encodeValue("int256", json.Number("-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, res, []byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	})
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	priv, err := key.MarshallPrivateKey()
+	assert.NoError(t, err)
+
+	content, err := keystore.EncryptV3(priv, "password", 2)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "key.json"), content, 0600))
+
+	// other files in the directory are skipped
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("keys"), 0600))
+
+	signer, err := NewKeystoreSigner(dir, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Accounts(), []ethgo.Address{key.Address()})
+
+	_, err = NewKeystoreSigner(dir, "wrong")
+	assert.Error(t, err)
+}
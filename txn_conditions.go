@@ -0,0 +1,123 @@
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/umbracle/ethgo"
+)
+
+// maxConditionsCost is the maximum number of storage roots and
+// slots checked by the conditions of a transaction
+const maxConditionsCost = 1000
+
+// conditionsError is the error returned when the conditions of a
+// transaction are not met (-32003) or are too expensive (-32005)
+type conditionsError struct {
+	code int
+	msg  string
+}
+
+func (c *conditionsError) Error() string {
+	return c.msg
+}
+
+// ErrorCode implements the jsonrpc.Error interface
+func (c *conditionsError) ErrorCode() int {
+	return c.code
+}
+
+func newConditionsError(format string, args ...interface{}) error {
+	return &conditionsError{code: -32003, msg: fmt.Sprintf(format, args...)}
+}
+
+// decodeConditions converts the conditions of the request
+func decodeConditions(args *conditionalArgs) (*TxConditions, error) {
+	if args == nil {
+		return nil, fmt.Errorf("conditions are empty")
+	}
+
+	toUint64 := func(n *argUint64) *uint64 {
+		if n == nil {
+			return nil
+		}
+		num := uint64(*n)
+		return &num
+	}
+	conditions := &TxConditions{
+		KnownAccounts:  map[ethgo.Address]*KnownAccount{},
+		BlockNumberMin: toUint64(args.BlockNumberMin),
+		BlockNumberMax: toUint64(args.BlockNumberMax),
+		TimestampMin:   toUint64(args.TimestampMin),
+		TimestampMax:   toUint64(args.TimestampMax),
+	}
+
+	cost := 0
+	for addr, account := range args.KnownAccounts {
+		if account == nil {
+			return nil, fmt.Errorf("known account %s is empty", addr)
+		}
+		if account.StorageRoot != nil {
+			cost++
+		} else {
+			cost += len(account.Slots)
+		}
+		conditions.KnownAccounts[addr] = &KnownAccount{
+			StorageRoot: account.StorageRoot,
+			Slots:       account.Slots,
+		}
+	}
+	if cost > maxConditionsCost {
+		return nil, &conditionsError{
+			code: -32005,
+			msg:  fmt.Sprintf("conditions cost %d exceeds the limit of %d", cost, maxConditionsCost),
+		}
+	}
+	return conditions, nil
+}
+
+// checkConditions checks the conditions of the transaction at the header
+func (e *Eth) checkConditions(conditions *TxConditions, header *ethgo.Block) error {
+	if min := conditions.BlockNumberMin; min != nil && header.Number < *min {
+		return newConditionsError("block number %d is lower than the minimum %d", header.Number, *min)
+	}
+	if max := conditions.BlockNumberMax; max != nil && header.Number > *max {
+		return newConditionsError("block number %d is higher than the maximum %d", header.Number, *max)
+	}
+	if min := conditions.TimestampMin; min != nil && header.Timestamp < *min {
+		return newConditionsError("timestamp %d is lower than the minimum %d", header.Timestamp, *min)
+	}
+	if max := conditions.TimestampMax; max != nil && header.Timestamp > *max {
+		return newConditionsError("timestamp %d is higher than the maximum %d", header.Timestamp, *max)
+	}
+
+	for addr, known := range conditions.KnownAccounts {
+		if known.StorageRoot != nil {
+			account, found, err := e.b.GetAccount(header.StateRoot, addr)
+			if err != nil {
+				return err
+			}
+			root := emptyRoot
+			if found {
+				root = account.Root
+			}
+			if root != *known.StorageRoot {
+				return newConditionsError("storage root of account %s does not match", addr)
+			}
+			continue
+		}
+		for slot, value := range known.Slots {
+			res, found, err := e.b.GetStorage(header.StateRoot, addr, slot)
+			if err != nil {
+				return err
+			}
+			current := ethgo.Hash{}
+			if found {
+				current = ethgo.BytesToHash(res)
+			}
+			if current != value {
+				return newConditionsError("storage slot %s of account %s does not match", slot, addr)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+)
+
+type mockSubmitStore struct {
+	mockAccountStore
+	header *ethgo.Block
+	raw    []byte
+	opts   *SubmitOptions
+}
+
+func (m *mockSubmitStore) ChainID() uint64 {
+	return 5
+}
+
+func (m *mockSubmitStore) Header() *ethgo.Block {
+	return m.header
+}
+
+func (m *mockSubmitStore) AddTxWithOptions(raw []byte, opts *SubmitOptions) (ethgo.Hash, error) {
+	m.raw = raw
+	m.opts = opts
+	return ethgo.Hash{}, nil
+}
+
+func TestEth_SendRawTransactionConditional(t *testing.T) {
+	b := &mockSubmitStore{header: &ethgo.Block{Number: 10, Timestamp: 100}}
+	b.AddAccount(addr1).account.Root = hash1
+	b.AddAccount(addr2).Storage(hash1, hash2)
+
+	eth := NewEth(b)
+
+	send := func(args *conditionalArgs) error {
+		b.raw, b.opts = nil, nil
+		raw := signedRawTxn(t, &ethgo.Transaction{To: &addr1, Gas: 21000, GasPrice: 1}, 5)
+
+		hash, err := eth.SendRawTransactionConditional(raw, args)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, b.raw, raw)
+		assert.Equal(t, hash.Bytes(), ethgo.Keccak256(raw))
+		return nil
+	}
+
+	// the conditions are met
+	err := send(&conditionalArgs{
+		KnownAccounts: map[ethgo.Address]*knownAccountArgs{
+			addr1: {StorageRoot: &hash1},
+			addr2: {Slots: map[ethgo.Hash]ethgo.Hash{hash1: hash2}},
+		},
+		BlockNumberMin: argUintPtr(10),
+		TimestampMax:   argUintPtr(100),
+	})
+	assert.NoError(t, err)
+
+	// the conditions are passed to the backend
+	assert.False(t, b.opts.Private)
+	assert.Equal(t, *b.opts.Conditions.BlockNumberMin, uint64(10))
+	assert.Equal(t, *b.opts.Conditions.KnownAccounts[addr1].StorageRoot, hash1)
+
+	cases := []*conditionalArgs{
+		{BlockNumberMin: argUintPtr(11)},
+		{BlockNumberMax: argUintPtr(9)},
+		{TimestampMin: argUintPtr(101)},
+		{TimestampMax: argUintPtr(99)},
+		// the storage root does not match
+		{KnownAccounts: map[ethgo.Address]*knownAccountArgs{addr1: {StorageRoot: &hash2}}},
+		// the account does not exist and has an empty storage root
+		{KnownAccounts: map[ethgo.Address]*knownAccountArgs{addr0: {StorageRoot: &hash1}}},
+		// the storage slot does not match
+		{KnownAccounts: map[ethgo.Address]*knownAccountArgs{addr2: {Slots: map[ethgo.Hash]ethgo.Hash{hash1: hash3}}}},
+	}
+	for _, c := range cases {
+		err := send(c)
+		assert.Error(t, err)
+		assert.Equal(t, err.(*conditionsError).ErrorCode(), -32003)
+		assert.Nil(t, b.raw)
+	}
+
+	// the conditions are too expensive
+	slots := map[ethgo.Hash]ethgo.Hash{}
+	for i := 0; i <= maxConditionsCost; i++ {
+		slots[ethgo.BytesToHash([]byte{byte(i >> 8), byte(i)})] = ethgo.Hash{}
+	}
+	err = send(&conditionalArgs{KnownAccounts: map[ethgo.Address]*knownAccountArgs{addr2: {Slots: slots}}})
+	assert.Equal(t, err.(*conditionsError).ErrorCode(), -32005)
+}
+
+func TestEth_SendPrivateTransaction(t *testing.T) {
+	b := &mockSubmitStore{header: &ethgo.Block{Number: 10}}
+	eth := NewEth(b)
+
+	raw := signedRawTxn(t, &ethgo.Transaction{To: &addr1, Gas: 21000, GasPrice: 1}, 5)
+
+	_, err := eth.SendPrivateTransaction(&privateTxnArgs{Tx: raw})
+	assert.NoError(t, err)
+	assert.True(t, b.opts.Private)
+	assert.Nil(t, b.opts.Conditions)
+
+	_, err = eth.SendPrivateTransaction(&privateTxnArgs{Tx: raw, MaxBlockNumber: argUintPtr(20)})
+	assert.NoError(t, err)
+	assert.Equal(t, *b.opts.Conditions.BlockNumberMax, uint64(20))
+
+	// the max block number is already included
+	_, err = eth.SendPrivateTransaction(&privateTxnArgs{Tx: raw, MaxBlockNumber: argUintPtr(9)})
+	assert.Error(t, err)
+
+	// the backend does not support private transactions
+	_, err = NewEth(&mockRawTxnStore{}).SendPrivateTransaction(&privateTxnArgs{Tx: raw})
+	assert.EqualError(t, err, "transaction submission options are not supported")
+}
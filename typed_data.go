@@ -0,0 +1,292 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/umbracle/ethgo"
+)
+
+// typedDataField is a field of a struct type of the typed data
+type typedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// typedData is the structured data signed with eth_signTypedData_v4 (EIP-712)
+type typedData struct {
+	Types       map[string][]typedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+func (t *typedData) UnmarshalJSON(data []byte) error {
+	type typedDataAlias typedData
+
+	// the numbers are decoded as json.Number to keep the precision
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var res typedDataAlias
+	if err := dec.Decode(&res); err != nil {
+		return err
+	}
+	if res.PrimaryType == "" {
+		return fmt.Errorf("primaryType is empty")
+	}
+	if _, ok := res.Types[res.PrimaryType]; !ok {
+		return fmt.Errorf("primary type %s is not defined", res.PrimaryType)
+	}
+	if _, ok := res.Types["EIP712Domain"]; !ok {
+		return fmt.Errorf("EIP712Domain type is not defined")
+	}
+	*t = typedData(res)
+	return nil
+}
+
+// hash returns the hash of the typed data signed by the sender
+func (t *typedData) hash() ([]byte, error) {
+	domainHash, err := t.hashStruct("EIP712Domain", t.Domain)
+	if err != nil {
+		return nil, err
+	}
+	msgHash, err := t.hashStruct(t.PrimaryType, t.Message)
+	if err != nil {
+		return nil, err
+	}
+	buf := []byte{0x19, 0x01}
+	buf = append(buf, domainHash...)
+	buf = append(buf, msgHash...)
+	return ethgo.Keccak256(buf), nil
+}
+
+func (t *typedData) hashStruct(typ string, data map[string]interface{}) ([]byte, error) {
+	buf := ethgo.Keccak256([]byte(t.encodeType(typ)))
+	for _, field := range t.Types[typ] {
+		val, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for field %s of type %s", field.Name, typ)
+		}
+		enc, err := t.encodeValue(field.Type, val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field %s: %v", field.Name, err)
+		}
+		buf = append(buf, enc...)
+	}
+	return ethgo.Keccak256(buf), nil
+}
+
+// encodeType returns the type followed by the struct types it references sorted by name
+func (t *typedData) encodeType(typ string) string {
+	deps := map[string]struct{}{}
+	t.dependencies(typ, deps)
+	delete(deps, typ)
+
+	names := []string{}
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := ""
+	for _, name := range append([]string{typ}, names...) {
+		params := []string{}
+		for _, field := range t.Types[name] {
+			params = append(params, field.Type+" "+field.Name)
+		}
+		res += name + "(" + strings.Join(params, ",") + ")"
+	}
+	return res
+}
+
+func (t *typedData) dependencies(typ string, deps map[string]struct{}) {
+	typ = typedDataBaseType(typ)
+	if _, ok := deps[typ]; ok {
+		return
+	}
+	if _, ok := t.Types[typ]; !ok {
+		return
+	}
+	deps[typ] = struct{}{}
+	for _, field := range t.Types[typ] {
+		t.dependencies(field.Type, deps)
+	}
+}
+
+// typedDataBaseType returns the type without the array suffixes
+func typedDataBaseType(typ string) string {
+	if indx := strings.Index(typ, "["); indx != -1 {
+		return typ[:indx]
+	}
+	return typ
+}
+
+// encodeValue returns the 32 bytes encoding of the value
+func (t *typedData) encodeValue(typ string, val interface{}) ([]byte, error) {
+	if strings.HasSuffix(typ, "]") {
+		indx := strings.LastIndex(typ, "[")
+		elemTyp := typ[:indx]
+
+		elems, ok := val.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected array for type %s", typ)
+		}
+		if size := typ[indx+1 : len(typ)-1]; size != "" {
+			num, err := strconv.Atoi(size)
+			if err != nil {
+				return nil, fmt.Errorf("invalid array type %s", typ)
+			}
+			if num != len(elems) {
+				return nil, fmt.Errorf("expected %d items for type %s", num, typ)
+			}
+		}
+		buf := []byte{}
+		for _, elem := range elems {
+			enc, err := t.encodeValue(elemTyp, elem)
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, enc...)
+		}
+		return ethgo.Keccak256(buf), nil
+	}
+
+	if _, ok := t.Types[typ]; ok {
+		data, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object for type %s", typ)
+		}
+		return t.hashStruct(typ, data)
+	}
+
+	switch {
+	case typ == "string":
+		str, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string")
+		}
+		return ethgo.Keccak256([]byte(str)), nil
+
+	case typ == "bytes":
+		buf, err := decodeTypedDataBytes(val)
+		if err != nil {
+			return nil, err
+		}
+		return ethgo.Keccak256(buf), nil
+
+	case typ == "bool":
+		b, ok := val.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool")
+		}
+		res := make([]byte, 32)
+		if b {
+			res[31] = 1
+		}
+		return res, nil
+
+	case typ == "address":
+		buf, err := decodeTypedDataBytes(val)
+		if err != nil {
+			return nil, err
+		}
+		if len(buf) != 20 {
+			return nil, fmt.Errorf("invalid address")
+		}
+		return leftPad(buf, 32), nil
+
+	case strings.HasPrefix(typ, "bytes"):
+		size, err := strconv.Atoi(typ[len("bytes"):])
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("unknown type %s", typ)
+		}
+		buf, err := decodeTypedDataBytes(val)
+		if err != nil {
+			return nil, err
+		}
+		if len(buf) > size {
+			return nil, fmt.Errorf("expected %d bytes for type %s", size, typ)
+		}
+		res := make([]byte, 32)
+		copy(res, buf)
+		return res, nil
+
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		signed := strings.HasPrefix(typ, "int")
+		size, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int"))
+		if err != nil || size < 8 || size > 256 || size%8 != 0 {
+			return nil, fmt.Errorf("unknown type %s", typ)
+		}
+		num, err := decodeTypedDataInteger(val)
+		if err != nil {
+			return nil, err
+		}
+		return encodeTypedDataInteger(num, size, signed)
+	}
+	return nil, fmt.Errorf("unknown type %s", typ)
+}
+
+func decodeTypedDataBytes(val interface{}) ([]byte, error) {
+	str, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected hex string")
+	}
+	if !strings.HasPrefix(str, "0x") {
+		return nil, fmt.Errorf("hex string without 0x prefix")
+	}
+	return hex.DecodeString(str[2:])
+}
+
+func decodeTypedDataInteger(val interface{}) (*big.Int, error) {
+	var str string
+	switch obj := val.(type) {
+	case json.Number:
+		str = obj.String()
+	case string:
+		str = obj
+	default:
+		return nil, fmt.Errorf("expected number")
+	}
+
+	base := 10
+	if strings.HasPrefix(str, "0x") {
+		str, base = str[2:], 16
+	}
+	num, ok := new(big.Int).SetString(str, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %s", str)
+	}
+	return num, nil
+}
+
+func encodeTypedDataInteger(num *big.Int, size int, signed bool) ([]byte, error) {
+	if !signed {
+		if num.Sign() < 0 || num.BitLen() > size {
+			return nil, fmt.Errorf("number %s overflows uint%d", num, size)
+		}
+		return leftPad(num.Bytes(), 32), nil
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(size-1))
+	if num.Cmp(limit) >= 0 || num.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("number %s overflows int%d", num, size)
+	}
+	if num.Sign() < 0 {
+		// two's complement
+		num = new(big.Int).Add(num, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	return leftPad(num.Bytes(), 32), nil
+}
+
+func leftPad(buf []byte, size int) []byte {
+	res := make([]byte, size)
+	copy(res[size-len(buf):], buf)
+	return res
+}
@@ -2,6 +2,8 @@ package jsonrpc
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"math/big"
 	"strconv"
 	"strings"
@@ -113,4 +115,453 @@ type txnArgs struct {
 	Input    *argBytes
 	Data     *argBytes
 	Nonce    *argUint64
+
+	// typed transaction values
+	Type                 *argUint64
+	ChainID              *argUint64
+	AccessList           []rpcAccessEntry
+	MaxFeePerGas         *argBytes
+	MaxPriorityFeePerGas *argBytes
+}
+
+// rpcSignTransactionResult is the signed transaction returned by eth_signTransaction
+type rpcSignTransactionResult struct {
+	Raw argBytes        `json:"raw"`
+	Tx  *rpcTransaction `json:"tx"`
+}
+
+// accountOverrideArgs is the override of an account for the call endpoints
+type accountOverrideArgs struct {
+	Nonce     *argUint64
+	Code      *argBytes
+	Balance   *argBig
+	State     map[ethgo.Hash]ethgo.Hash
+	StateDiff map[ethgo.Hash]ethgo.Hash
+}
+
+// stateOverrideArgs is the set of account overrides for the call endpoints
+type stateOverrideArgs map[ethgo.Address]*accountOverrideArgs
+
+// blockOverrideArgs is the override of the block context for the call endpoints
+type blockOverrideArgs struct {
+	Number   *argUint64
+	Time     *argUint64
+	GasLimit *argUint64
+	Coinbase *ethgo.Address
+	BaseFee  *argBig
+}
+
+// conditionalArgs are the conditions of eth_sendRawTransactionConditional
+type conditionalArgs struct {
+	KnownAccounts  map[ethgo.Address]*knownAccountArgs
+	BlockNumberMin *argUint64
+	BlockNumberMax *argUint64
+	TimestampMin   *argUint64
+	TimestampMax   *argUint64
+}
+
+// knownAccountArgs is either the storage root of an account or the values of some of its storage slots
+type knownAccountArgs struct {
+	StorageRoot *ethgo.Hash
+	Slots       map[ethgo.Hash]ethgo.Hash
+}
+
+func (k *knownAccountArgs) UnmarshalJSON(data []byte) error {
+	var root ethgo.Hash
+	if err := json.Unmarshal(data, &root); err == nil {
+		k.StorageRoot = &root
+		return nil
+	}
+	slots := map[ethgo.Hash]ethgo.Hash{}
+	if err := json.Unmarshal(data, &slots); err != nil {
+		return fmt.Errorf("known account must be a storage root or a map of storage slots")
+	}
+	k.Slots = slots
+	return nil
+}
+
+// privateTxnArgs is the transaction of eth_sendPrivateTransaction
+type privateTxnArgs struct {
+	Tx             argBytes
+	MaxBlockNumber *argUint64
+}
+
+// rpcAccessEntry is the jsonrpc representation of an access list entry
+type rpcAccessEntry struct {
+	Address     ethgo.Address `json:"address"`
+	StorageKeys []ethgo.Hash  `json:"storageKeys"`
+}
+
+func toRPCAccessList(list ethgo.AccessList) []rpcAccessEntry {
+	res := []rpcAccessEntry{}
+	for _, entry := range list {
+		keys := entry.Storage
+		if keys == nil {
+			keys = []ethgo.Hash{}
+		}
+		res = append(res, rpcAccessEntry{Address: entry.Address, StorageKeys: keys})
+	}
+	return res
+}
+
+// rpcSyncProgress is the jsonrpc representation of the sync progress
+type rpcSyncProgress struct {
+	StartingBlock argUint64 `json:"startingBlock"`
+	CurrentBlock  argUint64 `json:"currentBlock"`
+	HighestBlock  argUint64 `json:"highestBlock"`
+}
+
+// rpcAccessListResult is the jsonrpc representation of a created access list
+type rpcAccessListResult struct {
+	AccessList []rpcAccessEntry `json:"accessList"`
+	GasUsed    argUint64        `json:"gasUsed"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// rpcTransaction is the jsonrpc representation of a transaction
+type rpcTransaction struct {
+	BlockHash            *ethgo.Hash      `json:"blockHash"`
+	BlockNumber          *argUint64       `json:"blockNumber"`
+	From                 ethgo.Address    `json:"from"`
+	Gas                  argUint64        `json:"gas"`
+	GasPrice             argBig           `json:"gasPrice"`
+	MaxFeePerGas         *argBig          `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *argBig          `json:"maxPriorityFeePerGas,omitempty"`
+	Hash                 ethgo.Hash       `json:"hash"`
+	Input                argBytes         `json:"input"`
+	Nonce                argUint64        `json:"nonce"`
+	To                   *ethgo.Address   `json:"to"`
+	TransactionIndex     *argUint64       `json:"transactionIndex"`
+	Value                argBig           `json:"value"`
+	Type                 argUint64        `json:"type"`
+	AccessList           []rpcAccessEntry `json:"accessList,omitempty"`
+	ChainID              *argBig          `json:"chainId,omitempty"`
+	V                    argBig           `json:"v"`
+	R                    argBig           `json:"r"`
+	S                    argBig           `json:"s"`
+}
+
+func bigOrZero(b *big.Int) *big.Int {
+	if b == nil {
+		return new(big.Int)
+	}
+	return b
+}
+
+// toRPCTransaction converts the transaction to its jsonrpc representation. If the
+// block is not nil, the block fields are taken from the block and the index and the
+// gas price of dynamic fee transactions is the effective gas price with the base fee.
+// Otherwise, the block fields are taken from the transaction or are null if the
+// transaction is pending.
+func toRPCTransaction(txn *ethgo.Transaction, b *ethgo.Block, index uint64, baseFee *big.Int) *rpcTransaction {
+	res := &rpcTransaction{
+		From:     txn.From,
+		Gas:      argUint64(txn.Gas),
+		GasPrice: *argBigPtr(new(big.Int).SetUint64(txn.GasPrice)),
+		Hash:     txn.Hash,
+		Input:    argBytes(txn.Input),
+		Nonce:    argUint64(txn.Nonce),
+		To:       txn.To,
+		Value:    *argBigPtr(bigOrZero(txn.Value)),
+		Type:     argUint64(txn.Type),
+		V:        *argBigPtr(new(big.Int).SetBytes(txn.V)),
+		R:        *argBigPtr(new(big.Int).SetBytes(txn.R)),
+		S:        *argBigPtr(new(big.Int).SetBytes(txn.S)),
+	}
+	if res.Input == nil {
+		res.Input = argBytes{}
+	}
+
+	if txn.Type != ethgo.TransactionLegacy {
+		res.AccessList = toRPCAccessList(txn.AccessList)
+		res.ChainID = argBigPtr(bigOrZero(txn.ChainID))
+	}
+	if txn.Type == ethgo.TransactionDynamicFee {
+		res.MaxFeePerGas = argBigPtr(bigOrZero(txn.MaxFeePerGas))
+		res.MaxPriorityFeePerGas = argBigPtr(bigOrZero(txn.MaxPriorityFeePerGas))
+		res.GasPrice = *res.MaxFeePerGas
+		if b != nil && baseFee != nil {
+			res.GasPrice = *argBigPtr(effectiveGasPrice(txn, baseFee))
+		}
+	}
+
+	if b != nil {
+		res.BlockHash = &b.Hash
+		res.BlockNumber = argUintPtr(b.Number)
+		res.TransactionIndex = argUintPtr(index)
+	} else if txn.BlockHash != ethgo.ZeroHash {
+		res.BlockHash = &txn.BlockHash
+		res.BlockNumber = argUintPtr(txn.BlockNumber)
+		res.TransactionIndex = argUintPtr(txn.TxnIndex)
+	}
+	return res
+}
+
+// txnCount returns the number of transactions in the block
+func txnCount(b *ethgo.Block) uint64 {
+	if len(b.Transactions) != 0 {
+		return uint64(len(b.Transactions))
+	}
+	return uint64(len(b.TransactionsHashes))
+}
+
+// txnHashes returns the hashes of the transactions in the block
+func txnHashes(b *ethgo.Block) []ethgo.Hash {
+	if len(b.Transactions) == 0 {
+		return b.TransactionsHashes
+	}
+	hashes := make([]ethgo.Hash, 0, len(b.Transactions))
+	for _, txn := range b.Transactions {
+		hashes = append(hashes, txn.Hash)
+	}
+	return hashes
+}
+
+// txnByIndex returns the transaction at the index of the block
+// or nil if the index is out of range
+func txnByIndex(b *ethgo.Block, index uint64, baseFee *big.Int) *rpcTransaction {
+	if index >= uint64(len(b.Transactions)) {
+		return nil
+	}
+	return toRPCTransaction(b.Transactions[index], b, index, baseFee)
+}
+
+// rpcReceipt is the jsonrpc representation of a receipt
+type rpcReceipt struct {
+	TransactionHash   ethgo.Hash     `json:"transactionHash"`
+	TransactionIndex  argUint64      `json:"transactionIndex"`
+	BlockHash         ethgo.Hash     `json:"blockHash"`
+	BlockNumber       argUint64      `json:"blockNumber"`
+	From              ethgo.Address  `json:"from"`
+	To                *ethgo.Address `json:"to"`
+	GasUsed           argUint64      `json:"gasUsed"`
+	CumulativeGasUsed argUint64      `json:"cumulativeGasUsed"`
+	EffectiveGasPrice argBig         `json:"effectiveGasPrice"`
+	ContractAddress   *ethgo.Address `json:"contractAddress"`
+	Logs              []*ethgo.Log   `json:"logs"`
+	LogsBloom         argBytes       `json:"logsBloom"`
+	Type              argUint64      `json:"type"`
+	Status            argUint64      `json:"status"`
+}
+
+// toRPCReceipts converts the receipts of the block to their jsonrpc representation.
+// The block fields, the transaction fields and the log indexes are derived from the
+// block and its transactions, which must be in the same order as the receipts.
+func toRPCReceipts(b *ethgo.Block, baseFee *big.Int, receipts []*ethgo.Receipt) ([]*rpcReceipt, error) {
+	if len(receipts) != len(b.Transactions) {
+		return nil, fmt.Errorf("block %d has %d transactions but %d receipts", b.Number, len(b.Transactions), len(receipts))
+	}
+
+	res := []*rpcReceipt{}
+	logIndex := uint64(0)
+	for indx, receipt := range receipts {
+		res = append(res, toRPCReceipt(receipt, b.Transactions[indx], b.Hash, b.Number, uint64(indx), logIndex, baseFee))
+		logIndex += uint64(len(receipt.Logs))
+	}
+	return res, nil
+}
+
+// toRPCReceipt converts the receipt to its jsonrpc representation with the given block
+// context. The logs are indexed in the block starting from the log index.
+func toRPCReceipt(receipt *ethgo.Receipt, txn *ethgo.Transaction, blockHash ethgo.Hash, blockNumber, index, logIndex uint64, baseFee *big.Int) *rpcReceipt {
+	res := &rpcReceipt{
+		TransactionHash:   txn.Hash,
+		TransactionIndex:  argUint64(index),
+		BlockHash:         blockHash,
+		BlockNumber:       argUint64(blockNumber),
+		From:              txn.From,
+		To:                txn.To,
+		GasUsed:           argUint64(receipt.GasUsed),
+		CumulativeGasUsed: argUint64(receipt.CumulativeGasUsed),
+		EffectiveGasPrice: *argBigPtr(effectiveGasPrice(txn, baseFee)),
+		Logs:              []*ethgo.Log{},
+		LogsBloom:         argBytes(receipt.LogsBloom),
+		Type:              argUint64(txn.Type),
+		Status:            argUint64(receipt.Status),
+	}
+	if txn.To == nil && receipt.ContractAddress != ethgo.ZeroAddress {
+		contractAddress := receipt.ContractAddress
+		res.ContractAddress = &contractAddress
+	}
+	for indx, log := range receipt.Logs {
+		l := *log
+		l.Removed = false
+		l.LogIndex = logIndex + uint64(indx)
+		l.TransactionIndex = index
+		l.TransactionHash = txn.Hash
+		l.BlockHash = blockHash
+		l.BlockNumber = blockNumber
+		res.Logs = append(res.Logs, &l)
+	}
+	if len(res.LogsBloom) == 0 {
+		res.LogsBloom = argBytes(createBloom(receipt.Logs))
+	}
+	return res
+}
+
+// effectiveGasPrice returns the gas price paid by the transaction
+func effectiveGasPrice(txn *ethgo.Transaction, baseFee *big.Int) *big.Int {
+	if txn.Type != ethgo.TransactionDynamicFee {
+		return new(big.Int).SetUint64(txn.GasPrice)
+	}
+	price := new(big.Int).Set(bigOrZero(txn.MaxFeePerGas))
+	if baseFee != nil {
+		tipPrice := new(big.Int).Add(baseFee, bigOrZero(txn.MaxPriorityFeePerGas))
+		if tipPrice.Cmp(price) < 0 {
+			price = tipPrice
+		}
+	}
+	return price
+}
+
+// rpcBlock is the jsonrpc representation of a block
+type rpcBlock struct {
+	Number           argUint64     `json:"number"`
+	Hash             ethgo.Hash    `json:"hash"`
+	ParentHash       ethgo.Hash    `json:"parentHash"`
+	Sha3Uncles       ethgo.Hash    `json:"sha3Uncles"`
+	TransactionsRoot ethgo.Hash    `json:"transactionsRoot"`
+	StateRoot        ethgo.Hash    `json:"stateRoot"`
+	ReceiptsRoot     ethgo.Hash    `json:"receiptsRoot"`
+	Miner            ethgo.Address `json:"miner"`
+	LogsBloom        argBytes      `json:"logsBloom"`
+	Difficulty       argBig        `json:"difficulty"`
+	TotalDifficulty  argBig        `json:"totalDifficulty"`
+	ExtraData        argBytes      `json:"extraData"`
+	Size             argUint64     `json:"size"`
+	GasLimit         argUint64     `json:"gasLimit"`
+	GasUsed          argUint64     `json:"gasUsed"`
+	Timestamp        argUint64     `json:"timestamp"`
+	MixHash          ethgo.Hash    `json:"mixHash"`
+	Nonce            argBytes      `json:"nonce"`
+	BaseFeePerGas    *argBig       `json:"baseFeePerGas,omitempty"`
+	Transactions     []interface{} `json:"transactions"`
+	Uncles           []ethgo.Hash  `json:"uncles"`
+}
+
+// toRPCBlock converts the block to its jsonrpc representation. The transactions
+// are either full transaction objects or only the hashes. The header fields
+// that are not known are zero.
+func toRPCBlock(b *ethgo.Block, full bool, baseFee *big.Int, fields *HeaderFields) *rpcBlock {
+	if fields == nil {
+		fields = &HeaderFields{}
+	}
+	res := &rpcBlock{
+		Number:           argUint64(b.Number),
+		Hash:             b.Hash,
+		ParentHash:       b.ParentHash,
+		Sha3Uncles:       b.Sha3Uncles,
+		TransactionsRoot: b.TransactionsRoot,
+		StateRoot:        b.StateRoot,
+		ReceiptsRoot:     b.ReceiptsRoot,
+		Miner:            b.Miner,
+		LogsBloom:        argBytes(fields.LogsBloom),
+		Difficulty:       *argBigPtr(bigOrZero(b.Difficulty)),
+		TotalDifficulty:  *argBigPtr(bigOrZero(fields.TotalDifficulty)),
+		ExtraData:        argBytes(b.ExtraData),
+		Size:             argUint64(fields.Size),
+		GasLimit:         argUint64(b.GasLimit),
+		GasUsed:          argUint64(b.GasUsed),
+		Timestamp:        argUint64(b.Timestamp),
+		MixHash:          fields.MixHash,
+		Nonce:            argBytes(fields.Nonce[:]),
+		Transactions:     []interface{}{},
+		Uncles:           b.Uncles,
+	}
+	if baseFee != nil {
+		res.BaseFeePerGas = argBigPtr(baseFee)
+	}
+	if res.ExtraData == nil {
+		res.ExtraData = argBytes{}
+	}
+	if len(res.LogsBloom) != bloomSize {
+		res.LogsBloom = make(argBytes, bloomSize)
+	}
+	if res.Uncles == nil {
+		res.Uncles = []ethgo.Hash{}
+	}
+
+	if len(b.Transactions) != 0 {
+		for indx, txn := range b.Transactions {
+			if full {
+				res.Transactions = append(res.Transactions, toRPCTransaction(txn, b, uint64(indx), baseFee))
+			} else {
+				res.Transactions = append(res.Transactions, txn.Hash)
+			}
+		}
+	} else {
+		for _, hash := range b.TransactionsHashes {
+			res.Transactions = append(res.Transactions, hash)
+		}
+	}
+	return res
+}
+
+var (
+	// emptyCodeHash is the hash of an empty code
+	emptyCodeHash = ethgo.HexToHash("0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470")
+
+	// emptyRoot is the root of an empty trie
+	emptyRoot = ethgo.HexToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+)
+
+// rpcAccountProof is the jsonrpc representation of an account proof (EIP-1186)
+type rpcAccountProof struct {
+	Address      ethgo.Address      `json:"address"`
+	AccountProof []argBytes         `json:"accountProof"`
+	Balance      argBig             `json:"balance"`
+	CodeHash     ethgo.Hash         `json:"codeHash"`
+	Nonce        argUint64          `json:"nonce"`
+	StorageHash  ethgo.Hash         `json:"storageHash"`
+	StorageProof []*rpcStorageProof `json:"storageProof"`
+}
+
+// rpcStorageProof is the jsonrpc representation of a storage slot proof
+type rpcStorageProof struct {
+	Key   ethgo.Hash `json:"key"`
+	Value argBig     `json:"value"`
+	Proof []argBytes `json:"proof"`
+}
+
+func toRPCProofNodes(nodes [][]byte) []argBytes {
+	res := []argBytes{}
+	for _, node := range nodes {
+		res = append(res, argBytes(node))
+	}
+	return res
+}
+
+// toRPCAccountProof converts the proof to its jsonrpc representation. The
+// fields of accounts that do not exist are set to the empty account values.
+func toRPCAccountProof(addr ethgo.Address, keys []ethgo.Hash, proof *AccountProof) (*rpcAccountProof, error) {
+	res := &rpcAccountProof{
+		Address:      addr,
+		AccountProof: toRPCProofNodes(proof.Proof),
+		Balance:      *argBigPtr(new(big.Int)),
+		CodeHash:     emptyCodeHash,
+		StorageHash:  emptyRoot,
+		StorageProof: []*rpcStorageProof{},
+	}
+	if acc := proof.Account; acc != nil {
+		res.Balance = *argBigPtr(bigOrZero(acc.Balance))
+		res.Nonce = argUint64(acc.Nonce)
+		if len(acc.CodeHash) != 0 {
+			res.CodeHash = ethgo.BytesToHash(acc.CodeHash)
+		}
+		if acc.Root != ethgo.ZeroHash {
+			res.StorageHash = acc.Root
+		}
+	}
+	for indx, key := range keys {
+		storage := proof.StorageProof[indx]
+		if storage == nil {
+			return nil, fmt.Errorf("storage proof for key %s not found", key)
+		}
+		res.StorageProof = append(res.StorageProof, &rpcStorageProof{
+			Key:   key,
+			Value: *argBigPtr(new(big.Int).SetBytes(storage.Value)),
+			Proof: toRPCProofNodes(storage.Proof),
+		})
+	}
+	return res, nil
 }
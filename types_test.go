@@ -70,6 +70,34 @@ func TestDecode_TxnArgs(t *testing.T) {
 				Value: &hex,
 			},
 		},
+		{
+			data: `{
+				"to": "{{.Addr}}",
+				"type": "0x10",
+				"chainId": "0x10",
+				"maxFeePerGas": "0x01",
+				"maxPriorityFeePerGas": "0x01",
+				"accessList": [
+					{
+						"address": "{{.Addr}}",
+						"storageKeys": ["{{.Hash}}"]
+					}
+				]
+			}`,
+			res: &txnArgs{
+				To:                   &addr,
+				Type:                 &num,
+				ChainID:              &num,
+				MaxFeePerGas:         &hex,
+				MaxPriorityFeePerGas: &hex,
+				AccessList: []rpcAccessEntry{
+					{
+						Address:     addr,
+						StorageKeys: []ethgo.Hash{{}},
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -92,3 +120,57 @@ func TestDecode_TxnArgs(t *testing.T) {
 		}
 	}
 }
+
+func TestDecode_OverrideArgs(t *testing.T) {
+	data := `{
+		"0x0000000000000000000000000000000000000001": {
+			"balance": "0x10",
+			"nonce": "0x10",
+			"code": "0x01",
+			"stateDiff": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"
+			}
+		}
+	}`
+
+	var state stateOverrideArgs
+	assert.NoError(t, json.Unmarshal([]byte(data), &state))
+
+	account := state[ethgo.Address{19: 0x1}]
+	assert.Equal(t, (*big.Int)(account.Balance).Uint64(), uint64(16))
+	assert.Equal(t, uint64(*account.Nonce), uint64(16))
+	assert.Equal(t, []byte(*account.Code), []byte{0x1})
+	assert.Nil(t, account.State)
+	assert.Equal(t, account.StateDiff[ethgo.Hash{31: 0x1}], ethgo.Hash{31: 0x2})
+
+	var block blockOverrideArgs
+	assert.NoError(t, json.Unmarshal([]byte(`{"number": "0x10", "baseFee": "0x10", "coinbase": "0x0000000000000000000000000000000000000001"}`), &block))
+	assert.Equal(t, uint64(*block.Number), uint64(16))
+	assert.Equal(t, (*big.Int)(block.BaseFee).Uint64(), uint64(16))
+	assert.Equal(t, *block.Coinbase, ethgo.Address{19: 0x1})
+}
+
+func TestDecode_ConditionalArgs(t *testing.T) {
+	data := `{
+		"knownAccounts": {
+			"0x0000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"0x0000000000000000000000000000000000000002": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"
+			}
+		},
+		"blockNumberMax": "0x10",
+		"timestampMin": "0x20"
+	}`
+
+	var args conditionalArgs
+	assert.NoError(t, json.Unmarshal([]byte(data), &args))
+
+	assert.Equal(t, *args.KnownAccounts[ethgo.Address{19: 0x1}].StorageRoot, ethgo.Hash{31: 0x1})
+	assert.Equal(t, args.KnownAccounts[ethgo.Address{19: 0x2}].Slots[ethgo.Hash{31: 0x1}], ethgo.Hash{31: 0x2})
+	assert.Equal(t, uint64(*args.BlockNumberMax), uint64(16))
+	assert.Equal(t, uint64(*args.TimestampMin), uint64(32))
+	assert.Nil(t, args.BlockNumberMin)
+
+	// the known account is neither a root nor a map of slots
+	assert.Error(t, json.Unmarshal([]byte(`{"knownAccounts": {"0x0000000000000000000000000000000000000001": 1}}`), &args))
+}